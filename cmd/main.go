@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"os"
 	"wallet_engine/cmd/server"
 	_ "wallet_engine/docs"
 	"wallet_engine/pkg/database"
@@ -19,6 +20,14 @@ import (
 // @license.url https://github.com/sguazu
 
 // @BasePath /v1
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
 	var DBConnection = database.NewDatabase()
 	err := server.Run(DBConnection)
@@ -26,5 +35,19 @@ func main() {
 		log.Fatal(err)
 		return
 	}
+
+	if len(os.Args) > 1 {
+		commands := map[string]func([]string) error{
+			"reconcile": server.Reconcile,
+			"verify":    server.Verify,
+		}
+		if command, ok := commands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
 	server.Injection()
 }
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"os"
+	"strconv"
+
+	uuid "github.com/satori/go.uuid"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/middleware"
+	"wallet_engine/pkg/config"
+	"wallet_engine/pkg/token"
+)
+
+// newVerifier builds the bearer token verifier from the JWT secret and, when configured, the RSA
+// public key file
+func newVerifier() (*token.Verifier, error) {
+	var publicKey []byte
+	if file := config.Instance.GetJWTPublicKeyFile(); file != "" {
+		pem, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		publicKey = pem
+	}
+	return token.NewVerifier(config.Instance.JWTSecret, publicKey, config.Instance.GetJWTIssuer(), config.Instance.GetJWTAudience())
+}
+
+// accountOwner resolves the customer holding the wallet with an account number
+func accountOwner(ws ports.IWalletService) middleware.OwnerLookup {
+	return func(id string) (uuid.UUID, error) {
+		accountNumber, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		wallet, err := ws.GetWalletByAccountNumber(accountNumber)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return wallet.Owner, nil
+	}
+}
+
+// holdOwner resolves the customer holding the wallet a hold reserves funds on
+func holdOwner(hs ports.IHoldService, ws ports.IWalletService) middleware.OwnerLookup {
+	return func(id string) (uuid.UUID, error) {
+		hold, err := hs.GetHoldByID(id)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return middleware.WalletOwner(ws)(hold.WalletID.String())
+	}
+}
+
+// scheduleOwner resolves the customer holding the wallet a scheduled transaction is paid from
+func scheduleOwner(ss ports.IScheduleService, ws ports.IWalletService) middleware.OwnerLookup {
+	return func(id string) (uuid.UUID, error) {
+		schedule, err := ss.GetSchedule(common.GetByIDRequest{ID: id})
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return middleware.WalletOwner(ws)(schedule.WalletID.String())
+	}
+}
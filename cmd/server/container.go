@@ -1,48 +1,252 @@
 package server
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	log "github.com/sirupsen/logrus"
 	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
 	"wallet_engine/internals/core/services"
 
 	"wallet_engine/internals/handlers"
+	"wallet_engine/internals/middleware"
 	"wallet_engine/internals/repositories"
 	"wallet_engine/pkg/config"
+	"wallet_engine/pkg/fx"
 	"wallet_engine/pkg/logger"
+	"wallet_engine/pkg/nuban"
+	"wallet_engine/pkg/worker"
 )
 
 // Injection inject all dependencies
 func Injection() {
-	var logging *log.Logger
+	logging := newLogger()
 
-	if config.Instance.Env == "development" {
-		logging = logger.NewLogger(log.New()).MakeLogger("logs/info", true)
-		logging.Info("Log setup complete")
-	} else {
-		logging = logger.NewLogger(log.New()).Hook()
+	rates, err := rateProvider()
+	if err != nil {
+		logging.Fatal(err)
+	}
+
+	var (
+		ginRoutes               = NewGinRouter(gin.Default())
+		walletRepository        = repositories.NewRepository[domain.Wallet](DBConnection)
+		transactionRepository   = repositories.NewRepository[domain.Transaction](DBConnection)
+		idempotencyRepository   = repositories.NewRepository[domain.IdempotencyKey](DBConnection)
+		quoteRepository         = repositories.NewRepository[domain.FXQuote](DBConnection)
+		limitRepository         = repositories.NewRepository[domain.TierLimit](DBConnection)
+		statusRepository        = repositories.NewRepository[domain.WalletStatusChange](DBConnection)
+		ledgerAccountRepository = repositories.NewRepository[domain.LedgerAccount](DBConnection)
+		journalEntryRepository  = repositories.NewRepository[domain.JournalEntry](DBConnection)
+		postingRepository       = repositories.NewRepository[domain.Posting](DBConnection)
+		ledgerService           = services.NewLedgerService(*ledgerAccountRepository, *journalEntryRepository, *postingRepository, *transactionRepository, logging)
+		ledgerHandler           = handlers.NewLedgerHandler(ledgerService, logging, "Ledger")
+		accountNumbers          = nuban.NewNUBAN(config.Instance.GetBankCode())
+		feeRepository           = repositories.NewRepository[domain.FeeSchedule](DBConnection)
+		customerRepository      = repositories.NewRepository[domain.Customer](DBConnection)
+		approvalRepository      = repositories.NewRepository[domain.ApprovalRequest](DBConnection)
+		auditRepository         = repositories.NewRepository[domain.AuditLog](DBConnection)
+		customerService         = services.NewCustomerService(*customerRepository, *walletRepository, logging, DBConnection)
+		customerHandler         = handlers.NewCustomerHandler(customerService, logging, "Customer")
+		feeService              = services.NewFeeService(*feeRepository, *repositories.NewRepository[domain.FeeBand](DBConnection), logging, DBConnection)
+		feeHandler              = handlers.NewFeeHandler(feeService, logging, "Fee schedule")
+		walletService           = services.NewWalletService(*walletRepository, *transactionRepository, *idempotencyRepository, *quoteRepository, *limitRepository, *statusRepository, *feeRepository, *customerRepository, *approvalRepository, ledgerService, accountNumbers, logging, DBConnection)
+		walletHandler           = handlers.NewWalletHandler(walletService, logging, "Wallet")
+		fxService               = services.NewFXService(*quoteRepository, rates, logging)
+		fxHandler               = handlers.NewFXHandler(fxService, logging, "Quote")
+		holdRepository          = repositories.NewRepository[domain.Hold](DBConnection)
+		holdService             = services.NewHoldService(*walletRepository, *holdRepository, walletService, logging, DBConnection)
+		holdHandler             = handlers.NewHoldHandler(holdService, logging, "Hold")
+		limitService            = services.NewLimitService(*limitRepository, logging)
+		limitHandler            = handlers.NewLimitHandler(limitService, logging, "Limit")
+		scheduleService         = services.NewScheduleService(*repositories.NewRepository[domain.ScheduledTransaction](DBConnection), *repositories.NewRepository[domain.ScheduledExecution](DBConnection), walletService, logging)
+		scheduleHandler         = handlers.NewScheduleHandler(scheduleService, walletService, logging, "Schedule")
+		interestService         = services.NewInterestService(*walletRepository, *transactionRepository, *repositories.NewRepository[domain.InterestAccrual](DBConnection), walletService, domain.PayoutCycle(config.Instance.GetInterestPayoutCycle()), logging, DBConnection)
+		interestHandler         = handlers.NewInterestHandler(interestService, logging, "Interest")
+		reconciliationService   = services.NewReconciliationService(*walletRepository, *transactionRepository, logging)
+		reconciliationHandler   = handlers.NewReconciliationHandler(reconciliationService, logging, "Chain")
+	)
+
+	verifier, err := newVerifier()
+	if err != nil {
+		logging.Fatal(err)
 	}
 
 	var (
-		ginRoutes             = NewGinRouter(gin.Default())
-		walletRepository      = repositories.NewRepository[domain.Wallet](DBConnection)
-		transactionRepository = repositories.NewRepository[domain.Transaction](DBConnection)
-		walletService         = services.NewWalletService(*walletRepository, *transactionRepository, logging, DBConnection)
-		walletHandler         = handlers.NewWalletHandler(walletService, logging, "Wallet")
+		apiKeyService = services.NewAPIKeyService(*repositories.NewRepository[domain.APIKey](DBConnection), logging)
+		apiKeyHandler = handlers.NewAPIKeyHandler(apiKeyService, logging, "API key")
+		adminService  = services.NewAdminService(*walletRepository, *auditRepository, walletService, config.Instance.GetAdjustmentSupervisorThreshold(), logging, DBConnection)
+		adminHandler  = handlers.NewAdminHandler(adminService, logging, "Wallet")
+
+		approvalService = services.NewApprovalService(*walletRepository, *approvalRepository, *auditRepository, walletService, holdService, logging, DBConnection)
+		approvalHandler = handlers.NewApprovalHandler(approvalService, logging, "Approval request")
+
+		read     = middleware.RequireScope(domain.WALLET_READ_SCOPE)
+		write    = middleware.RequireScope(domain.WALLET_WRITE_SCOPE)
+		transact = middleware.RequireScope(domain.TRANSACTION_WRITE_SCOPE)
+		admin    = middleware.RequireAdmin()
+
+		operator   = middleware.RequireRole(domain.OPERATOR_ROLE)
+		supervisor = middleware.RequireRole(domain.SUPERVISOR_ROLE)
+
+		ownsWallet   = middleware.OwnsResource(middleware.Param("id"), middleware.WalletOwner(walletService))
+		ownsCustomer = middleware.OwnsCustomer(middleware.Param("id"))
+		ownsHold     = middleware.OwnsResource(middleware.Param("id"), holdOwner(holdService, walletService))
+		ownsSchedule = middleware.OwnsResource(middleware.Param("id"), scheduleOwner(scheduleService, walletService))
+		ownsAccount  = middleware.OwnsResource(middleware.Param("account_number"), accountOwner(walletService))
 	)
 
 	v1 := ginRoutes.GROUP("v1")
+	v1.Use(middleware.Authenticate(verifier, apiKeyService, logging))
+
 	wallet := v1.Group("/wallet")
-	wallet.GET("/:id", walletHandler.GetWalletByID)
-	wallet.POST("/", walletHandler.CreateWallet)
-	wallet.DELETE("/:id", walletHandler.DeleteWallet)
-	wallet.PATCH("/:id/activate", walletHandler.UpdateWallet)
-	wallet.PATCH("/:id", walletHandler.TransactionWallet)
+	wallet.GET("/:id", read, ownsWallet, walletHandler.GetWalletByID)
+	wallet.GET("/:id/transactions", read, ownsWallet, walletHandler.GetTransactions)
+	wallet.GET("/", admin, walletHandler.GetWallets)
+	wallet.POST("/", write, walletHandler.CreateWallet)
+	wallet.DELETE("/:id", write, ownsWallet, walletHandler.DeleteWallet)
+	wallet.PATCH("/:id/activate", admin, walletHandler.UpdateWallet)
+	wallet.GET("/:id/status-history", read, ownsWallet, walletHandler.GetStatusChanges)
+	wallet.GET("/:id/balance-check", read, ownsWallet, walletHandler.DeriveBalance)
+	wallet.GET("/:id/verify", read, ownsWallet, reconciliationHandler.VerifyChain)
+	wallet.GET("/:id/schedules", read, ownsWallet, scheduleHandler.GetWalletSchedules)
+	wallet.GET("/:id/interest", read, ownsWallet, interestHandler.GetAccruals)
+	wallet.POST("/:id/close", write, ownsWallet, walletHandler.CloseWallet)
+	wallet.POST("/:id/restore", admin, walletHandler.RestoreWallet)
+	wallet.PATCH("/:id", transact, ownsWallet, walletHandler.TransactionWallet)
+	wallet.POST("/:id/holds", transact, ownsWallet, holdHandler.CreateHold)
+
+	v1.POST("/transfers", transact, walletHandler.Transfer)
+	v1.GET("/accounts/:account_number", read, ownsAccount, walletHandler.GetWalletByAccountNumber)
+	v1.POST("/fx/quotes", transact, fxHandler.CreateQuote)
+
+	customer := v1.Group("/customers")
+	customer.GET("/", admin, customerHandler.GetCustomers)
+	customer.POST("/", admin, customerHandler.CreateCustomer)
+	customer.GET("/:id", read, ownsCustomer, customerHandler.GetCustomer)
+	customer.PATCH("/:id", admin, customerHandler.UpdateCustomer)
+	customer.DELETE("/:id", admin, customerHandler.DeleteCustomer)
+	customer.GET("/:id/wallets", read, ownsCustomer, customerHandler.GetCustomerWallets)
+
+	transaction := v1.Group("/transactions")
+	transaction.POST("/:id/reverse", admin, walletHandler.ReverseTransaction)
+
+	hold := v1.Group("/holds")
+	hold.GET("/:id", read, ownsHold, holdHandler.GetHoldByID)
+	hold.POST("/:id/capture", transact, ownsHold, holdHandler.CaptureHold)
+	hold.POST("/:id/release", transact, ownsHold, holdHandler.ReleaseHold)
+
+	ledger := v1.Group("/ledger", admin)
+	ledger.GET("/accounts", ledgerHandler.GetAccounts)
+	ledger.GET("/trial-balance", ledgerHandler.GetTrialBalance)
 
-	err := ginRoutes.SERVE()
+	schedule := v1.Group("/schedules")
+	schedule.POST("/", transact, scheduleHandler.CreateSchedule)
+	schedule.GET("/:id", read, ownsSchedule, scheduleHandler.GetSchedule)
+	schedule.POST("/:id/cancel", transact, ownsSchedule, scheduleHandler.CancelSchedule)
+
+	fee := v1.Group("/fees", admin)
+	fee.GET("/", feeHandler.GetFeeSchedules)
+	fee.POST("/", feeHandler.SaveFeeSchedule)
+	fee.DELETE("/:id", feeHandler.DeleteFeeSchedule)
+
+	limit := v1.Group("/limits", admin)
+	limit.GET("/", limitHandler.GetLimits)
+	limit.PUT("/:tier", limitHandler.UpdateLimit)
+
+	apiKey := v1.Group("/api-keys", admin)
+	apiKey.GET("/", apiKeyHandler.GetAPIKeys)
+	apiKey.POST("/", apiKeyHandler.CreateAPIKey)
+	apiKey.POST("/:id/revoke", apiKeyHandler.RevokeAPIKey)
+
+	staff := v1.Group("/admin", middleware.RequireRole(domain.VIEWER_ROLE))
+	staff.GET("/wallets", walletHandler.GetWallets)
+	staff.GET("/wallets/:id", walletHandler.GetWalletByID)
+	staff.GET("/wallets/:id/transactions", walletHandler.GetTransactions)
+	staff.GET("/wallets/:id/status-history", walletHandler.GetStatusChanges)
+	staff.GET("/accounts/:account_number", walletHandler.GetWalletByAccountNumber)
+	staff.GET("/customers/:id", customerHandler.GetCustomer)
+	staff.GET("/audit-logs", adminHandler.GetAuditLogs)
+	staff.POST("/wallets/:id/freeze", operator, adminHandler.FreezeWallet)
+	staff.POST("/wallets/:id/unfreeze", operator, adminHandler.UnfreezeWallet)
+	staff.POST("/wallets/:id/adjustments", operator, adminHandler.AdjustBalance)
+	staff.PATCH("/wallets/:id/status", supervisor, adminHandler.ChangeStatus)
+	staff.GET("/approvals", approvalHandler.GetApprovals)
+	staff.GET("/approvals/:id", approvalHandler.GetApproval)
+	staff.POST("/approvals/:id/approve", operator, approvalHandler.Approve)
+	staff.POST("/approvals/:id/reject", operator, approvalHandler.Reject)
+
+	go worker.Every(config.Instance.GetHoldExpiryInterval(), func() {
+		if expired, err := holdService.ExpireHolds(); err != nil {
+			logging.Error(err)
+		} else if expired > 0 {
+			logging.Infof("expired %d holds", expired)
+		}
+	})
+
+	go worker.Every(config.Instance.GetApprovalExpiryInterval(), func() {
+		if expired, err := approvalService.ExpireApprovals(); err != nil {
+			logging.Error(err)
+		} else if expired > 0 {
+			logging.Infof("expired %d approval requests", expired)
+		}
+	})
+
+	go worker.Every(config.Instance.GetWalletPurgeInterval(), func() {
+		if purged, err := walletService.PurgeWallets(); err != nil {
+			logging.Error(err)
+		} else if purged > 0 {
+			logging.Infof("purged %d deleted wallets", purged)
+		}
+	})
+
+	go worker.Every(config.Instance.GetScheduleInterval(), func() {
+		if ran, err := scheduleService.RunDueSchedules(); err != nil {
+			logging.Error(err)
+		} else if ran > 0 {
+			logging.Infof("ran %d scheduled transactions", ran)
+		}
+	})
+
+	accrualHour, accrualMinute := config.Instance.GetInterestAccrualAt()
+	go worker.Daily(accrualHour, accrualMinute, func() {
+		if accrued, err := interestService.AccrueInterest(time.Now()); err != nil {
+			logging.Error(err)
+		} else if accrued > 0 {
+			logging.Infof("accrued %d days of interest", accrued)
+		}
+	})
+
+	hour, minute := config.Instance.GetReconciliationAt()
+	go worker.Daily(hour, minute, func() {
+		if err := reconcile(reconciliationService, config.Instance.GetReconciliationDir(), logging); err != nil {
+			logging.Error(err)
+		}
+	})
+
+	err = ginRoutes.SERVE()
 
 	if err != nil {
 		return
 	}
 
 }
+
+// rateProvider loads exchange rates from the configured file, or starts with none so only
+// same currency transfers are possible
+func rateProvider() (ports.IFXRateProvider, error) {
+	if path := config.Instance.FXRatesFile; path != nil && *path != "" {
+		return fx.LoadStaticRateProvider(*path)
+	}
+	return fx.NewStaticRateProvider(nil)
+}
+
+// newLogger writes to the local log files in development and to the configured hook elsewhere
+func newLogger() *log.Logger {
+	if config.Instance.Env == "development" {
+		logging := logger.NewLogger(log.New()).MakeLogger("logs/info", true)
+		logging.Info("Log setup complete")
+		return logging
+	}
+	return logger.NewLogger(log.New()).Hook()
+}
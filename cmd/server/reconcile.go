@@ -0,0 +1,76 @@
+package server
+
+import (
+	"flag"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/core/services"
+	"wallet_engine/internals/repositories"
+	"wallet_engine/pkg/config"
+)
+
+// Reconcile runs a single reconciliation from the command line, writing its report to the
+// directory given by -out. It fails when any discrepancy is found so schedulers can alert on it
+func Reconcile(args []string) error {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	dir := flags.String("out", config.Instance.GetReconciliationDir(), "directory the report is written to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	logging := newLogger()
+	return reconcile(newReconciliationService(logging), *dir, logging)
+}
+
+// Verify walks the hash chain of every wallet id given and fails on the first broken one
+func Verify(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: verify <wallet id>...")
+	}
+
+	logging := newLogger()
+	reconciliationService := newReconciliationService(logging)
+
+	for _, id := range args {
+		verification, err := reconciliationService.VerifyChain(common.GetByIDRequest{ID: id})
+		if err != nil {
+			return fmt.Errorf("wallet %v: %w", id, err)
+		}
+		if !verification.Intact {
+			return fmt.Errorf("wallet %v: transaction %v tampered, %v", id, verification.FirstTampered, verification.Reason)
+		}
+		logging.Infof("wallet %v: %d transactions verified, %d unsealed", id, verification.Verified, verification.Unsealed)
+	}
+	return nil
+}
+
+func newReconciliationService(logging *log.Logger) ports.IReconciliationService {
+	walletRepository := repositories.NewRepository[domain.Wallet](DBConnection)
+	transactionRepository := repositories.NewRepository[domain.Transaction](DBConnection)
+	return services.NewReconciliationService(*walletRepository, *transactionRepository, logging)
+}
+
+func reconcile(reconciliationService ports.IReconciliationService, dir string, logging *log.Logger) error {
+	report, err := reconciliationService.Reconcile()
+	if err != nil {
+		return err
+	}
+
+	paths, err := reconciliationService.WriteReport(report, dir)
+	if err != nil {
+		return err
+	}
+
+	logging.Infof("reconciled %d wallets and %d transactions, report written to %v",
+		report.WalletsScanned, report.TransactionsScanned, paths)
+
+	if len(report.Discrepancies) > 0 {
+		return fmt.Errorf("reconciliation found %d discrepancies", len(report.Discrepancies))
+	}
+	return nil
+}
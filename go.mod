@@ -5,6 +5,7 @@ go 1.18
 require (
 	github.com/gin-gonic/gin v1.7.7
 	github.com/go-openapi/swag v0.21.1
+	github.com/golang-jwt/jwt/v4 v4.5.2
 	github.com/joho/godotenv v1.4.0
 	github.com/olivere/elastic/v7 v7.0.4
 	github.com/satori/go.uuid v1.2.0
@@ -0,0 +1,46 @@
+package common
+
+import "wallet_engine/internals/core/domain"
+
+// AdminReasonRequest DTO giving why a member of staff is acting on a wallet
+type AdminReasonRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// AdminStatusRequest DTO to move a wallet to any status its lifecycle allows
+type AdminStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+// AdjustmentRequest DTO to correct a wallet's balance by hand
+type AdjustmentRequest struct {
+	TransactionType string `json:"transaction_type" binding:"required,oneof=credit debit"`
+	Amount          int64  `json:"amount" binding:"required,gt=0"`
+	Reason          string `json:"reason" binding:"required"`
+	Reference       string `json:"reference,omitempty"`
+}
+
+// AuditLogFilterRequest DTO to narrow the audit trail
+type AuditLogFilterRequest struct {
+	Actor      string `form:"actor"`
+	Action     string `form:"action"`
+	ResourceID string `form:"resource_id" binding:"omitempty,uuid"`
+}
+
+// AuditLogPage DTO holding a page of audit records
+type AuditLogPage struct {
+	Limit      int               `json:"limit"`
+	Page       int               `json:"page"`
+	Sort       string            `json:"sort"`
+	TotalRows  int64             `json:"total_rows"`
+	TotalPages int               `json:"total_pages"`
+	Rows       []domain.AuditLog `json:"rows"`
+}
+
+// GetAuditLogsResponse DTO return a page of audit records
+type GetAuditLogsResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Data    AuditLogPage `json:"data"`
+}
@@ -0,0 +1,36 @@
+package common
+
+import "wallet_engine/internals/core/domain"
+
+// CreateAPIKeyRequest DTO to issue an API key
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required,min=1,dive,oneof=wallet:read wallet:write transaction:write wallet:admin"`
+}
+
+// IssuedAPIKey DTO holding a new API key, the only time the key itself is ever shown
+type IssuedAPIKey struct {
+	APIKey domain.APIKey `json:"api_key"`
+	Key    string        `json:"key"`
+}
+
+// CreateAPIKeyResponse DTO return a new API key
+type CreateAPIKeyResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Data    IssuedAPIKey `json:"data"`
+}
+
+// GetAPIKeyResponse DTO return an API key
+type GetAPIKeyResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message"`
+	Data    domain.APIKey `json:"data"`
+}
+
+// GetAPIKeysResponse DTO return every API key
+type GetAPIKeysResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    []domain.APIKey `json:"data"`
+}
@@ -0,0 +1,47 @@
+package common
+
+import "wallet_engine/internals/core/domain"
+
+// ApprovalFilterRequest DTO to narrow the approvals queue, pending requests are listed when no status is given
+type ApprovalFilterRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending_approval approved rejected expired"`
+	WalletID string `form:"wallet_id" binding:"omitempty,uuid"`
+	Maker    string `form:"maker"`
+}
+
+// ApprovalResult DTO holding an approved request and the debit it posted, an approved hold posts none
+type ApprovalResult struct {
+	Approval    domain.ApprovalRequest `json:"approval"`
+	Transaction *domain.Transaction    `json:"transaction,omitempty"`
+}
+
+// ApprovalPage DTO holding a page of approval requests
+type ApprovalPage struct {
+	Limit      int                      `json:"limit"`
+	Page       int                      `json:"page"`
+	Sort       string                   `json:"sort"`
+	TotalRows  int64                    `json:"total_rows"`
+	TotalPages int                      `json:"total_pages"`
+	Rows       []domain.ApprovalRequest `json:"rows"`
+}
+
+// GetApprovalResponse DTO return approval request
+type GetApprovalResponse struct {
+	Success bool                   `json:"success"`
+	Message string                 `json:"message"`
+	Data    domain.ApprovalRequest `json:"data"`
+}
+
+// GetApprovalsResponse DTO return a page of approval requests
+type GetApprovalsResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Data    ApprovalPage `json:"data"`
+}
+
+// ApproveResponse DTO return an approved request and its debit
+type ApproveResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message"`
+	Data    ApprovalResult `json:"data"`
+}
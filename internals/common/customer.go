@@ -0,0 +1,51 @@
+package common
+
+import "wallet_engine/internals/core/domain"
+
+// CreateCustomerRequest DTO to create customer
+type CreateCustomerRequest struct {
+	Name  string `json:"name" binding:"required"`
+	Email string `json:"email" binding:"required,email"`
+	Phone string `json:"phone,omitempty"`
+	Tier  string `json:"tier,omitempty" binding:"omitempty,oneof=tier1 tier2 tier3"`
+}
+
+// UpdateCustomerRequest DTO to update customer, fields left out are kept
+type UpdateCustomerRequest struct {
+	Name   *string `json:"name,omitempty" binding:"omitempty,min=1"`
+	Email  *string `json:"email,omitempty" binding:"omitempty,email"`
+	Phone  *string `json:"phone,omitempty"`
+	Tier   *string `json:"tier,omitempty" binding:"omitempty,oneof=tier1 tier2 tier3"`
+	Status *string `json:"status,omitempty" binding:"omitempty,oneof=active suspended"`
+}
+
+// GetCustomerResponse DTO return customer
+type GetCustomerResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    domain.Customer `json:"data"`
+}
+
+// CustomerPage DTO holding a page of customers
+type CustomerPage struct {
+	Limit      int               `json:"limit"`
+	Page       int               `json:"page"`
+	Sort       string            `json:"sort"`
+	TotalRows  int64             `json:"total_rows"`
+	TotalPages int               `json:"total_pages"`
+	Rows       []domain.Customer `json:"rows"`
+}
+
+// GetCustomersResponse DTO return a page of customers
+type GetCustomersResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Data    CustomerPage `json:"data"`
+}
+
+// GetCustomerWalletsResponse DTO return every wallet a customer holds
+type GetCustomerWalletsResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    []domain.Wallet `json:"data"`
+}
@@ -0,0 +1,37 @@
+package common
+
+import "wallet_engine/internals/core/domain"
+
+// FeeBandRequest DTO pricing amounts up to up_to within a tiered schedule, omit up_to on the last band
+type FeeBandRequest struct {
+	UpTo       int64 `json:"up_to" binding:"min=0"`
+	FlatAmount int64 `json:"flat_amount" binding:"min=0"`
+	RateBps    int64 `json:"rate_bps" binding:"min=0,max=10000"`
+}
+
+// SaveFeeScheduleRequest DTO to create or replace the fee schedule of a purpose, tier and currency
+type SaveFeeScheduleRequest struct {
+	Purpose    string           `json:"purpose" binding:"required,oneof=deposit withdrawal transfer"`
+	Tier       string           `json:"tier" binding:"omitempty,oneof=tier1 tier2 tier3"`
+	Currency   string           `json:"currency,omitempty"`
+	Type       string           `json:"type" binding:"required,oneof=flat percentage tiered"`
+	FlatAmount int64            `json:"flat_amount" binding:"min=0"`
+	RateBps    int64            `json:"rate_bps" binding:"min=0,max=10000"`
+	MinFee     int64            `json:"min_fee" binding:"min=0"`
+	MaxFee     int64            `json:"max_fee" binding:"min=0"`
+	Bands      []FeeBandRequest `json:"bands,omitempty" binding:"dive"`
+}
+
+// GetFeeScheduleResponse DTO return fee schedule
+type GetFeeScheduleResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Data    domain.FeeSchedule `json:"data"`
+}
+
+// GetFeeSchedulesResponse DTO return every fee schedule
+type GetFeeSchedulesResponse struct {
+	Success bool                 `json:"success"`
+	Message string               `json:"message"`
+	Data    []domain.FeeSchedule `json:"data"`
+}
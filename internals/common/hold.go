@@ -0,0 +1,36 @@
+package common
+
+import "wallet_engine/internals/core/domain"
+
+// CreateHoldRequest DTO to reserve funds on a wallet
+type CreateHoldRequest struct {
+	Amount    int64  `json:"amount" binding:"required,gt=0"`
+	Currency  string `json:"currency,omitempty"`
+	Reference string `json:"reference,omitempty"`
+	ExpiresIn int64  `json:"expires_in,omitempty" binding:"omitempty,gt=0"`
+}
+
+// CaptureHoldRequest DTO to settle a hold, the full held amount is captured when amount is omitted
+type CaptureHoldRequest struct {
+	Amount int64 `json:"amount,omitempty" binding:"omitempty,gt=0"`
+}
+
+// CaptureHoldResult DTO holding a captured hold and the debit it produced
+type CaptureHoldResult struct {
+	Hold        domain.Hold        `json:"hold"`
+	Transaction domain.Transaction `json:"transaction"`
+}
+
+// GetHoldResponse DTO return hold
+type GetHoldResponse struct {
+	Success bool        `json:"success"`
+	Message string      `json:"message"`
+	Data    domain.Hold `json:"data"`
+}
+
+// CaptureHoldResponse DTO return captured hold
+type CaptureHoldResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Data    CaptureHoldResult `json:"data"`
+}
@@ -0,0 +1,10 @@
+package common
+
+import "wallet_engine/internals/core/domain"
+
+// GetInterestAccrualsResponse DTO return a savings wallet's daily interest accruals
+type GetInterestAccrualsResponse struct {
+	Success bool                     `json:"success"`
+	Message string                   `json:"message"`
+	Data    []domain.InterestAccrual `json:"data"`
+}
@@ -0,0 +1,48 @@
+package common
+
+import "wallet_engine/internals/core/domain"
+
+// LedgerAccountFilterRequest DTO to narrow the chart of accounts
+type LedgerAccountFilterRequest struct {
+	Type     string `form:"type" binding:"omitempty,oneof=asset liability revenue expense"`
+	Currency string `form:"currency"`
+}
+
+// GetLedgerAccountsResponse DTO return ledger accounts
+type GetLedgerAccountsResponse struct {
+	Success bool                   `json:"success"`
+	Message string                 `json:"message"`
+	Data    []domain.LedgerAccount `json:"data"`
+}
+
+// TrialBalanceLine DTO holding the balance of one ledger account on its debit or credit side
+type TrialBalanceLine struct {
+	Code     string             `json:"code"`
+	Name     string             `json:"name"`
+	Type     domain.AccountType `json:"type"`
+	Currency domain.Currency    `json:"currency"`
+	Debit    int64              `json:"debit"`
+	Credit   int64              `json:"credit"`
+}
+
+// TrialBalanceTotal DTO holding the debit and credit totals of one currency
+type TrialBalanceTotal struct {
+	Currency domain.Currency `json:"currency"`
+	Debit    int64           `json:"debit"`
+	Credit   int64           `json:"credit"`
+	Balanced bool            `json:"balanced"`
+}
+
+// TrialBalance DTO listing every ledger account with a balance and whether the books balance
+type TrialBalance struct {
+	Lines    []TrialBalanceLine  `json:"lines"`
+	Totals   []TrialBalanceTotal `json:"totals"`
+	Balanced bool                `json:"balanced"`
+}
+
+// GetTrialBalanceResponse DTO return the trial balance
+type GetTrialBalanceResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Data    TrialBalance `json:"data"`
+}
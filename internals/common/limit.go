@@ -0,0 +1,31 @@
+package common
+
+import "wallet_engine/internals/core/domain"
+
+// GetByTierRequest DTO to get the limits of a tier
+type GetByTierRequest struct {
+	Tier string `uri:"tier" binding:"required"`
+}
+
+// UpdateTierLimitRequest DTO to change a tier's limits, a zero switches a rule off
+type UpdateTierLimitRequest struct {
+	MaxSingleTransaction *int64 `json:"max_single_transaction,omitempty" binding:"omitempty,min=0"`
+	DailyDebitLimit      *int64 `json:"daily_debit_limit,omitempty" binding:"omitempty,min=0"`
+	MonthlyDebitLimit    *int64 `json:"monthly_debit_limit,omitempty" binding:"omitempty,min=0"`
+	MaxTransactions      *int64 `json:"max_transactions,omitempty" binding:"omitempty,min=0"`
+	WindowSeconds        *int64 `json:"window_seconds,omitempty" binding:"omitempty,min=0"`
+}
+
+// GetTierLimitResponse DTO return tier limit
+type GetTierLimitResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    domain.TierLimit `json:"data"`
+}
+
+// GetTierLimitsResponse DTO return every tier limit
+type GetTierLimitsResponse struct {
+	Success bool               `json:"success"`
+	Message string             `json:"message"`
+	Data    []domain.TierLimit `json:"data"`
+}
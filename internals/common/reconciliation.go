@@ -0,0 +1,46 @@
+package common
+
+import (
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+
+	"wallet_engine/internals/core/domain"
+)
+
+// Discrepancy DTO describing one problem a reconciliation found
+type Discrepancy struct {
+	WalletID      uuid.UUID              `json:"wallet_id"`
+	AccountID     int64                  `json:"account_id"`
+	TransactionID *uuid.UUID             `json:"transaction_id,omitempty"`
+	Kind          domain.DiscrepancyKind `json:"kind"`
+	Expected      int64                  `json:"expected"`
+	Actual        int64                  `json:"actual"`
+}
+
+// ChainVerification DTO reporting whether a wallet's hash chain is intact
+type ChainVerification struct {
+	WalletID      uuid.UUID  `json:"wallet_id"`
+	Entries       int64      `json:"entries"`
+	Unsealed      int64      `json:"unsealed"`
+	Verified      int64      `json:"verified"`
+	Intact        bool       `json:"intact"`
+	FirstTampered *uuid.UUID `json:"first_tampered,omitempty"`
+	Reason        string     `json:"reason,omitempty"`
+}
+
+// GetChainVerificationResponse DTO return a chain verification
+type GetChainVerificationResponse struct {
+	Success bool              `json:"success"`
+	Message string            `json:"message"`
+	Data    ChainVerification `json:"data"`
+}
+
+// ReconciliationReport DTO summarising a reconciliation run
+type ReconciliationReport struct {
+	StartedAt           time.Time     `json:"started_at"`
+	FinishedAt          time.Time     `json:"finished_at"`
+	WalletsScanned      int64         `json:"wallets_scanned"`
+	TransactionsScanned int64         `json:"transactions_scanned"`
+	Discrepancies       []Discrepancy `json:"discrepancies"`
+}
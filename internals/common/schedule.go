@@ -0,0 +1,37 @@
+package common
+
+import (
+	"time"
+
+	"wallet_engine/internals/core/domain"
+)
+
+// CreateScheduleRequest DTO to set up a standing order. Give either cron, a five field cron
+// expression in the server's time zone, or interval_seconds. With a destination wallet each run
+// is a transfer, otherwise it credits or debits the wallet with transaction_type and purpose
+type CreateScheduleRequest struct {
+	WalletID            string     `json:"wallet_id" binding:"required,uuid"`
+	DestinationWalletID string     `json:"destination_wallet_id,omitempty" binding:"omitempty,uuid"`
+	TransactionType     string     `json:"transaction_type,omitempty" binding:"omitempty,oneof=credit debit"`
+	Purpose             string     `json:"purpose,omitempty" binding:"omitempty,oneof=deposit withdrawal"`
+	Amount              int64      `json:"amount" binding:"required,gt=0"`
+	Currency            string     `json:"currency,omitempty"`
+	Cron                string     `json:"cron,omitempty"`
+	IntervalSeconds     int64      `json:"interval_seconds,omitempty" binding:"omitempty,min=60"`
+	StartAt             *time.Time `json:"start_at,omitempty"`
+	EndsAt              *time.Time `json:"ends_at,omitempty"`
+}
+
+// GetScheduleResponse DTO return scheduled transaction
+type GetScheduleResponse struct {
+	Success bool                        `json:"success"`
+	Message string                      `json:"message"`
+	Data    domain.ScheduledTransaction `json:"data"`
+}
+
+// GetSchedulesResponse DTO return a wallet's scheduled transactions
+type GetSchedulesResponse struct {
+	Success bool                          `json:"success"`
+	Message string                        `json:"message"`
+	Data    []domain.ScheduledTransaction `json:"data"`
+}
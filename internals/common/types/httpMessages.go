@@ -10,12 +10,34 @@ const (
 	CREATED Messages = "created successfully"
 	// CREATED_TRANSACTION creates types of response messages for post endpoint
 	CREATED_TRANSACTION Messages = "transaction created successfully"
+	// CREATED_TRANSFER creates types of response messages for transfer endpoint
+	CREATED_TRANSFER Messages = "transfer created successfully"
+	// REVERSED_TRANSACTION creates types of response messages for reversal endpoint
+	REVERSED_TRANSACTION Messages = "transaction reversed successfully"
 	// OKAY creates types of response messages for get endpoint
 	OKAY = "retrieved successfully"
 	// DELETED creates types of response messages for delete endpoint
 	DELETED = "deleted successfully"
 	// UPDATED creates types of response messages for patch endpoint
 	UPDATED = "updated successfully"
+	// CAPTURED creates types of response messages for capture endpoint
+	CAPTURED = "captured successfully"
+	// RELEASED creates types of response messages for release endpoint
+	RELEASED = "released successfully"
+	// CLOSED creates types of response messages for close endpoint
+	CLOSED = "closed successfully"
+	// RESTORED creates types of response messages for restore endpoint
+	RESTORED = "restored successfully"
+	// CANCELLED creates types of response messages for cancel endpoint
+	CANCELLED = "cancelled successfully"
+	// REVOKED creates types of response messages for revoke endpoint
+	REVOKED = "revoked successfully"
+	// PENDING_APPROVAL creates types of response messages for a transaction held for approval
+	PENDING_APPROVAL = "transaction is pending approval"
+	// APPROVED creates types of response messages for approve endpoint
+	APPROVED = "approved successfully"
+	// REJECTED creates types of response messages for reject endpoint
+	REJECTED = "rejected successfully"
 )
 
 // GetResponseMessage generates dynamic messages
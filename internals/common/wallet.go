@@ -1,6 +1,9 @@
 package common
 
 import (
+	"errors"
+	"time"
+
 	uuid "github.com/satori/go.uuid"
 
 	"wallet_engine/internals/core/domain"
@@ -8,15 +11,74 @@ import (
 
 // CreateWalletRequest DTO to create wallet
 type CreateWalletRequest struct {
-	Status string `json:"status" binding:"required"`
+	CustomerID  string `json:"customer_id" binding:"required,uuid"`
+	Status      string `json:"status" binding:"required,oneof=pending active"`
+	Currency    string `json:"currency,omitempty"`
+	ProductType string `json:"product_type,omitempty" binding:"omitempty,oneof=standard savings"`
 }
 
 // CreateTransactionRequest DTO to create transaction
 type CreateTransactionRequest struct {
-	TransactionType string `json:"transaction_type" binding:"required"`
-	Purpose         string `json:"purpose" binding:"required"`
-	Amount          int64  `json:"amount" binding:"required"`
+	TransactionType string `json:"transaction_type" binding:"required,oneof=credit debit"`
+	Purpose         string `json:"purpose" binding:"required,oneof=deposit withdrawal"`
+	Amount          int64  `json:"amount" binding:"required,gt=0"`
+	Currency        string `json:"currency,omitempty"`
 	AccountID       string `json:"account_id" binding:"required"`
+	Reference       string `json:"reference,omitempty"`
+}
+
+// CreateTransferRequest DTO to move funds between two wallets
+type CreateTransferRequest struct {
+	SourceWalletID      string `json:"source_wallet_id" binding:"required,uuid"`
+	DestinationWalletID string `json:"destination_wallet_id" binding:"required,uuid"`
+	Amount              int64  `json:"amount" binding:"required,gt=0"`
+	Currency            string `json:"currency,omitempty"`
+	QuoteID             string `json:"quote_id,omitempty"`
+	Reference           string `json:"reference,omitempty"`
+}
+
+// IdempotencyHeader DTO to read the idempotency key a client sends with a request
+type IdempotencyHeader struct {
+	Key string `header:"Idempotency-Key"`
+}
+
+// IdempotencyKey returns the key to deduplicate a request by, preferring the header over reference
+func (h IdempotencyHeader) IdempotencyKey(reference string) (string, error) {
+	if h.Key != "" && reference != "" && h.Key != reference {
+		return "", errors.New("Idempotency-Key header and reference must match when both are sent")
+	}
+	if h.Key != "" {
+		return h.Key, nil
+	}
+	return reference, nil
+}
+
+// CreateQuoteRequest DTO to quote a currency conversion
+type CreateQuoteRequest struct {
+	SourceCurrency      string `json:"source_currency" binding:"required"`
+	DestinationCurrency string `json:"destination_currency" binding:"required"`
+	SourceAmount        int64  `json:"source_amount" binding:"required,gt=0"`
+}
+
+// CreateQuoteResponse DTO return quote
+type CreateQuoteResponse struct {
+	Success bool           `json:"success"`
+	Message string         `json:"message"`
+	Data    domain.FXQuote `json:"data"`
+}
+
+// TransferResponse DTO holding both legs of a transfer
+type TransferResponse struct {
+	Reference string             `json:"reference"`
+	Debit     domain.Transaction `json:"debit"`
+	Credit    domain.Transaction `json:"credit"`
+}
+
+// CreateTransferResponse DTO return transfer
+type CreateTransferResponse struct {
+	Success bool             `json:"success"`
+	Message string           `json:"message"`
+	Data    TransferResponse `json:"data"`
 }
 
 // GetTransactionResponse DTO to create transaction
@@ -24,11 +86,66 @@ type GetTransactionResponse struct {
 	TransactionType string `json:"transaction_type"`
 	Purpose         string `json:"purpose"`
 	Amount          int64  `json:"amount"`
+	Currency        string `json:"currency"`
 	AccountID       string `json:"account_id"`
 	BalanceBefore   int64  `json:"balance_before"`
 	BalanceAfter    int64  `json:"balance_after"`
 }
 
+// TransactionFilterRequest DTO to filter and sort a wallet's transaction history
+type TransactionFilterRequest struct {
+	TransactionType string     `form:"transaction_type" binding:"omitempty,oneof=credit debit"`
+	Purpose         string     `form:"purpose"`
+	MinAmount       *int64     `form:"min_amount" binding:"omitempty,min=0"`
+	MaxAmount       *int64     `form:"max_amount" binding:"omitempty,min=0"`
+	From            *time.Time `form:"from" time_format:"2006-01-02"`
+	To              *time.Time `form:"to" time_format:"2006-01-02"`
+	SortBy          string     `form:"sort_by,default=created_at" binding:"oneof=created_at amount"`
+	Order           string     `form:"order,default=desc" binding:"oneof=asc desc"`
+	IncludeDeleted  bool       `form:"include_deleted"`
+}
+
+// WalletFilterRequest DTO to narrow a wallet listing
+type WalletFilterRequest struct {
+	Status         string `form:"status"`
+	Currency       string `form:"currency"`
+	IncludeDeleted bool   `form:"include_deleted"`
+}
+
+// WalletPage DTO holding a page of wallets
+type WalletPage struct {
+	Limit      int             `json:"limit"`
+	Page       int             `json:"page"`
+	Sort       string          `json:"sort"`
+	TotalRows  int64           `json:"total_rows"`
+	TotalPages int             `json:"total_pages"`
+	Rows       []domain.Wallet `json:"rows"`
+}
+
+// GetWalletsResponse DTO return a page of wallets
+type GetWalletsResponse struct {
+	Success bool       `json:"success"`
+	Message string     `json:"message"`
+	Data    WalletPage `json:"data"`
+}
+
+// TransactionPage DTO holding a page of transactions
+type TransactionPage struct {
+	Limit      int                  `json:"limit"`
+	Page       int                  `json:"page"`
+	Sort       string               `json:"sort"`
+	TotalRows  int64                `json:"total_rows"`
+	TotalPages int                  `json:"total_pages"`
+	Rows       []domain.Transaction `json:"rows"`
+}
+
+// GetTransactionsResponse DTO return a page of transactions
+type GetTransactionsResponse struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    TransactionPage `json:"data"`
+}
+
 // CreateWalletResponse DTO return wallet
 type CreateWalletResponse struct {
 	Success bool          `json:"success"`
@@ -45,11 +162,14 @@ type CreateTransactionResponse struct {
 
 // GetWalletResponse DTO
 type GetWalletResponse struct {
-	ID        uuid.UUID    `json:"id" binding:"required"`
-	Owner     uuid.UUID    `json:"owner" binding:"required"`
-	Balance   int64        `json:"balance" binding:"required"`
-	Status    domain.State `json:"status"`
-	AccountID int32        `json:"account_id" binding:"required"`
+	ID        uuid.UUID       `json:"id" binding:"required"`
+	Owner     uuid.UUID       `json:"owner" binding:"required"`
+	Balance   int64           `json:"balance" binding:"required"`
+	Held      int64           `json:"held_balance"`
+	Available int64           `json:"available_balance"`
+	Currency  domain.Currency `json:"currency"`
+	Status    domain.State    `json:"status"`
+	AccountID int32           `json:"account_id" binding:"required"`
 }
 
 // GetWalletByIDRequest DTO to get wallet by id
@@ -57,9 +177,68 @@ type GetWalletByIDRequest struct {
 	ID string `uri:"id" binding:"required"`
 }
 
+// GetByAccountNumberRequest DTO to get wallet by account number
+type GetByAccountNumberRequest struct {
+	AccountNumber int64 `uri:"account_number" binding:"required"`
+}
+
+// GetWalletQuery DTO to choose what is loaded alongside a wallet
+type GetWalletQuery struct {
+	Include string `form:"include" binding:"omitempty,oneof=transactions"`
+}
+
 // UpdateWalletRequest DTO to update wallet
 type UpdateWalletRequest struct {
 	Status *string `json:"status,omitempty" form:"status"`
+	Reason string  `json:"reason,omitempty" form:"reason"`
+	Actor  string  `json:"actor,omitempty" form:"actor"`
+}
+
+// CloseWalletRequest DTO to close a wallet, sweeping any balance left to a destination wallet
+type CloseWalletRequest struct {
+	DestinationWalletID string `json:"destination_wallet_id" binding:"omitempty,uuid"`
+	Reason              string `json:"reason"`
+	Actor               string `json:"actor"`
+}
+
+// ClosedWallet DTO holding a closed wallet and the transfer that emptied it, if any
+type ClosedWallet struct {
+	Wallet domain.Wallet     `json:"wallet"`
+	Sweep  *TransferResponse `json:"sweep,omitempty"`
+}
+
+// CloseWalletResponse DTO return a closed wallet
+type CloseWalletResponse struct {
+	Success bool         `json:"success"`
+	Message string       `json:"message"`
+	Data    ClosedWallet `json:"data"`
+}
+
+// LedgerBalance DTO comparing a wallet's stored balance with the one derived from its journal
+type LedgerBalance struct {
+	WalletID       uuid.UUID       `json:"wallet_id"`
+	Currency       domain.Currency `json:"currency"`
+	Balance        int64           `json:"balance"`
+	DerivedBalance int64           `json:"derived_balance"`
+	Credits        int64           `json:"credits"`
+	Debits         int64           `json:"debits"`
+	Entries        int64           `json:"entries"`
+	Drift          int64           `json:"drift"`
+	Balanced       bool            `json:"balanced"`
+}
+
+// GetLedgerBalanceResponse DTO return a wallet's balance check
+type GetLedgerBalanceResponse struct {
+	Success bool          `json:"success"`
+	Message string        `json:"message"`
+	Data    LedgerBalance `json:"data"`
+}
+
+// GetStatusChangesResponse DTO return a wallet's status history
+type GetStatusChangesResponse struct {
+	Success bool                        `json:"success"`
+	Message string                      `json:"message"`
+	Data    []domain.WalletStatusChange `json:"data"`
 }
 
 // Error struct
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// ApprovalStatus defines the state of a transaction waiting on a second person
+type ApprovalStatus string
+
+const (
+	// APPROVAL_PENDING a request whose funds are held until it is approved, rejected or expires
+	APPROVAL_PENDING ApprovalStatus = "pending_approval"
+
+	// APPROVAL_APPROVED a request that was approved and posted
+	APPROVAL_APPROVED ApprovalStatus = "approved"
+
+	// APPROVAL_REJECTED a request that was turned down and gave its funds back
+	APPROVAL_REJECTED ApprovalStatus = "rejected"
+
+	// APPROVAL_EXPIRED a request nobody decided on in time, its funds were given back
+	APPROVAL_EXPIRED ApprovalStatus = "expired"
+)
+
+// ApprovalKind defines what a request does once it is approved
+type ApprovalKind string
+
+const (
+	// APPROVAL_TRANSACTION a debit posted against the wallet
+	APPROVAL_TRANSACTION ApprovalKind = "transaction"
+
+	// APPROVAL_TRANSFER a transfer from the wallet to the destination wallet
+	APPROVAL_TRANSFER ApprovalKind = "transfer"
+
+	// APPROVAL_HOLD a hold reserving the wallet's funds until it is captured
+	APPROVAL_HOLD ApprovalKind = "hold"
+
+	// APPROVAL_CLOSURE a closure of the wallet that sweeps its balance to the destination wallet
+	APPROVAL_CLOSURE ApprovalKind = "closure"
+)
+
+// ApprovalRequest model holds a high-value debit back until a checker other than its maker
+// approves it. The debit's amount stays on hold against the wallet until then
+type ApprovalRequest struct {
+	Base
+	Kind            ApprovalKind   `json:"kind" gorm:"not null;default:'transaction'"`
+	WalletID        uuid.UUID      `json:"wallet_id" gorm:"type:uuid;not null;index"`
+	TransactionType TxnType        `json:"transaction_type" gorm:"not null"`
+	Purpose         PurposeType    `json:"purpose" gorm:"not null"`
+	Amount          int64          `json:"amount" gorm:"not null"`
+	Currency        Currency       `json:"currency" gorm:"type:varchar(3);not null"`
+	Reference       string         `json:"reference,omitempty" gorm:"index"`
+	RequestHash     string         `json:"-"`
+	Narration       string         `json:"narration,omitempty"`
+	Maker           string         `json:"maker" gorm:"not null;index"`
+	Checker         string         `json:"checker,omitempty"`
+	Reason          string         `json:"reason,omitempty"`
+	Status          ApprovalStatus `json:"status" gorm:"not null;index"`
+	ExpiresAt       time.Time      `json:"expires_at" gorm:"not null;index"`
+	DecidedAt       *time.Time     `json:"decided_at,omitempty"`
+	TransactionID   *uuid.UUID     `json:"transaction_id,omitempty" gorm:"type:uuid"`
+
+	DestinationWalletID *uuid.UUID `json:"destination_wallet_id,omitempty" gorm:"type:uuid"`
+	QuoteID             *uuid.UUID `json:"quote_id,omitempty" gorm:"type:uuid"`
+	HoldExpiresIn       int64      `json:"hold_expires_in,omitempty"`
+	HoldID              *uuid.UUID `json:"hold_id,omitempty" gorm:"type:uuid"`
+}
+
+// PendingApprovalError is returned in place of a transaction that was queued for approval
+// instead of posted
+type PendingApprovalError struct {
+	Approval *ApprovalRequest
+}
+
+func (e *PendingApprovalError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrPendingApproval, e.Approval.ID)
+}
+
+// Unwrap lets errors.Is match ErrPendingApproval
+func (e *PendingApprovalError) Unwrap() error {
+	return ErrPendingApproval
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+)
+
+// AuditAction defines what a member of staff did
+type AuditAction string
+
+const (
+	// WALLET_STATUS_CHANGED a wallet was frozen, unfrozen or otherwise moved between states
+	WALLET_STATUS_CHANGED AuditAction = "wallet.status_changed"
+
+	// WALLET_ADJUSTED a wallet's balance was adjusted by hand
+	WALLET_ADJUSTED AuditAction = "wallet.adjusted"
+
+	// TRANSACTION_APPROVED a debit held for approval was approved and posted
+	TRANSACTION_APPROVED AuditAction = "transaction.approved"
+
+	// TRANSACTION_REJECTED a debit held for approval was rejected
+	TRANSACTION_REJECTED AuditAction = "transaction.rejected"
+)
+
+// AuditLog model records an action taken through the admin API, who took it, in which role and
+// why. Details holds the action's outcome as JSON
+type AuditLog struct {
+	Base
+	Actor        string      `json:"actor" gorm:"not null;index"`
+	Role         Role        `json:"role" gorm:"not null"`
+	Action       AuditAction `json:"action" gorm:"not null;index"`
+	ResourceType string      `json:"resource_type" gorm:"not null"`
+	ResourceID   uuid.UUID   `json:"resource_id" gorm:"type:uuid;not null;index"`
+	Reason       string      `json:"reason" gorm:"not null"`
+	Details      string      `json:"details"`
+}
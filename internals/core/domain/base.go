@@ -12,7 +12,7 @@ type Base struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey;autoIncrement:false"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	DeletedAt *time.Time `sql:"index"`
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
 // BeforeCreate hooks run to before database insertion occurs to populate the ID field
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// Currency is an ISO 4217 alphabetic currency code
+type Currency string
+
+// DefaultCurrency is assigned to wallets created without a currency
+const DefaultCurrency Currency = "NGN"
+
+// exponents maps the ISO 4217 currencies we hold to the number of minor units in a major unit,
+// expressed as a power of ten. Amounts are always stored in minor units
+var exponents = map[Currency]int{
+	"AED": 2,
+	"AUD": 2,
+	"BHD": 3,
+	"BWP": 2,
+	"CAD": 2,
+	"CHF": 2,
+	"CNY": 2,
+	"EGP": 2,
+	"ETB": 2,
+	"EUR": 2,
+	"GBP": 2,
+	"GHS": 2,
+	"INR": 2,
+	"JOD": 3,
+	"JPY": 0,
+	"KES": 2,
+	"KRW": 0,
+	"KWD": 3,
+	"MAD": 2,
+	"NGN": 2,
+	"OMR": 3,
+	"RWF": 0,
+	"SAR": 2,
+	"TND": 3,
+	"TZS": 2,
+	"UGX": 0,
+	"USD": 2,
+	"XAF": 0,
+	"XOF": 0,
+	"ZAR": 2,
+	"ZMW": 2,
+}
+
+// ParseCurrency normalises code and checks it is a supported ISO 4217 currency
+func ParseCurrency(code string) (Currency, error) {
+	currency := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if _, ok := exponents[currency]; !ok {
+		return "", ErrUnsupportedCurrency
+	}
+	return currency, nil
+}
+
+// Valid reports whether the currency is a supported ISO 4217 code
+func (c Currency) Valid() bool {
+	_, ok := exponents[c]
+	return ok
+}
+
+// Exponent returns the ISO 4217 minor unit exponent of the currency
+func (c Currency) Exponent() int {
+	return exponents[c]
+}
+
+// Currencies returns every supported currency in alphabetical order
+func Currencies() []Currency {
+	currencies := make([]Currency, 0, len(exponents))
+	for currency := range exponents {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i] < currencies[j] })
+	return currencies
+}
@@ -0,0 +1,23 @@
+package domain
+
+// CustomerStatus defines the state of a customer
+type CustomerStatus string
+
+const (
+	// CUSTOMER_ACTIVE a customer who may open wallets
+	CUSTOMER_ACTIVE CustomerStatus = "active"
+
+	// CUSTOMER_SUSPENDED a customer barred from opening wallets
+	CUSTOMER_SUSPENDED CustomerStatus = "suspended"
+)
+
+// Customer model owns wallets, a wallet's Owner is its customer's ID. Tier is the customer's KYC
+// tier, the limit tier every wallet they hold is on
+type Customer struct {
+	Base
+	Name   string         `json:"name" gorm:"not null"`
+	Email  string         `json:"email" gorm:"not null;uniqueIndex"`
+	Phone  string         `json:"phone"`
+	Tier   Tier           `json:"tier" gorm:"not null;default:'tier1'"`
+	Status CustomerStatus `json:"status" gorm:"not null;default:'active';index"`
+}
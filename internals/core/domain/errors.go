@@ -0,0 +1,134 @@
+package domain
+
+import "errors"
+
+var (
+	// ErrInvalidTransactionType is returned for a transaction that is neither a credit nor a debit
+	ErrInvalidTransactionType = errors.New("transaction type must be credit or debit")
+
+	// ErrInvalidAmount is returned for a transaction that does not move a positive amount
+	ErrInvalidAmount = errors.New("amount must be greater than zero")
+
+	// ErrInvalidRange is returned for a filter whose lower bound is above its upper bound
+	ErrInvalidRange = errors.New("filter range starts after it ends")
+
+	// ErrInsufficientBalance is returned when a debit exceeds the wallet balance
+	ErrInsufficientBalance = errors.New("insufficient balance")
+
+	// ErrSameWallet is returned when a transfer names the same wallet on both sides
+	ErrSameWallet = errors.New("source and destination wallet must be different")
+
+	// ErrIdempotencyConflict is returned when an idempotency key is replayed with a different payload
+	ErrIdempotencyConflict = errors.New("idempotency key has already been used for a different request")
+
+	// ErrInvalidAccountNumber is returned when an account number fails its checksum
+	ErrInvalidAccountNumber = errors.New("invalid account number")
+
+	// ErrAccountNumberExhausted is returned when no free account number could be allocated
+	ErrAccountNumberExhausted = errors.New("could not allocate a unique account number")
+
+	// ErrUnsupportedCurrency is returned for a code that is not a supported ISO 4217 currency
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+	// ErrCurrencyMismatch is returned when a transaction's currency differs from its wallet's
+	ErrCurrencyMismatch = errors.New("transaction currency does not match the wallet currency")
+
+	// ErrRateUnavailable is returned when no exchange rate is known for a currency pair
+	ErrRateUnavailable = errors.New("exchange rate unavailable for currency pair")
+
+	// ErrConversionTooSmall is returned when a converted amount rounds down to nothing
+	ErrConversionTooSmall = errors.New("amount is too small to convert")
+
+	// ErrQuoteRequired is returned for a transfer between currencies without an exchange rate quote
+	ErrQuoteRequired = errors.New("a quote is required to transfer between currencies")
+
+	// ErrQuoteExpired is returned when a quote is used after it expired
+	ErrQuoteExpired = errors.New("quote has expired")
+
+	// ErrQuoteUsed is returned when a quote has already settled a transfer
+	ErrQuoteUsed = errors.New("quote has already been used")
+
+	// ErrQuoteMismatch is returned when a quote does not match the transfer it is used for
+	ErrQuoteMismatch = errors.New("quote does not match the transfer currencies or amount")
+
+	// ErrHoldNotActive is returned when capturing or releasing a hold that is already settled
+	ErrHoldNotActive = errors.New("hold is no longer active")
+
+	// ErrHoldExpired is returned when capturing a hold past its expiry
+	ErrHoldExpired = errors.New("hold has expired")
+
+	// ErrCaptureExceedsHold is returned when capturing more than a hold reserved
+	ErrCaptureExceedsHold = errors.New("capture amount exceeds the held amount")
+
+	// ErrLimitExceeded is returned when a transaction breaches one of its wallet tier's limits
+	ErrLimitExceeded = errors.New("transaction limit exceeded")
+
+	// ErrInvalidStatus is returned for a status that is not part of the wallet lifecycle
+	ErrInvalidStatus = errors.New("invalid wallet status")
+
+	// ErrStatusTransition is returned when a wallet may not move from its status to the requested one
+	ErrStatusTransition = errors.New("wallet status transition not allowed")
+
+	// ErrDebitNotAllowed is returned when debiting a wallet whose status forbids it
+	ErrDebitNotAllowed = errors.New("wallet status does not allow debits")
+
+	// ErrCreditNotAllowed is returned when crediting a wallet whose status forbids it
+	ErrCreditNotAllowed = errors.New("wallet status does not allow credits")
+
+	// ErrWalletNotEmpty is returned when closing or removing a wallet that still holds funds
+	ErrWalletNotEmpty = errors.New("wallet still holds funds")
+
+	// ErrImmutableTransaction is returned when a journal entry is updated or deleted
+	ErrImmutableTransaction = errors.New("transactions are append-only and cannot be changed")
+
+	// ErrUnbalancedEntry is returned when a journal entry's postings do not sum to zero
+	ErrUnbalancedEntry = errors.New("journal entry postings do not balance")
+
+	// ErrInvalidFeeSchedule is returned for a fee schedule that cannot price a transaction
+	ErrInvalidFeeSchedule = errors.New("invalid fee schedule")
+
+	// ErrInvalidSchedule is returned for a scheduled transaction that cannot run
+	ErrInvalidSchedule = errors.New("invalid schedule")
+
+	// ErrScheduleNotActive is returned when cancelling a schedule that no longer runs
+	ErrScheduleNotActive = errors.New("schedule is not active")
+
+	// ErrInvalidProduct is returned for a wallet product or interest rate that does not fit together
+	ErrInvalidProduct = errors.New("invalid wallet product")
+
+	// ErrCustomerExists is returned when registering an email another customer already has
+	ErrCustomerExists = errors.New("a customer with this email already exists")
+
+	// ErrCustomerNotActive is returned when opening a wallet for a suspended customer
+	ErrCustomerNotActive = errors.New("customer is not active")
+
+	// ErrCustomerHasWallets is returned when removing a customer who still holds wallets
+	ErrCustomerHasWallets = errors.New("customer still holds wallets")
+
+	// ErrInvalidAPIKey is returned for an API key that is unknown, malformed or revoked
+	ErrInvalidAPIKey = errors.New("invalid api key")
+
+	// ErrAPIKeyRevoked is returned when revoking a key that is already revoked
+	ErrAPIKeyRevoked = errors.New("api key is already revoked")
+
+	// ErrSupervisorRequired is returned when an adjustment is too large for the staff member's role
+	ErrSupervisorRequired = errors.New("a supervisor is required for this adjustment")
+
+	// ErrPendingApproval is returned when a transaction was queued for a second person's approval
+	ErrPendingApproval = errors.New("transaction is pending approval")
+
+	// ErrSelfApproval is returned when the maker of a transaction tries to approve or reject it
+	ErrSelfApproval = errors.New("a transaction must be approved by someone other than its maker")
+
+	// ErrApprovalNotPending is returned when deciding on a request that was already decided or expired
+	ErrApprovalNotPending = errors.New("approval request is no longer pending")
+
+	// ErrApprovalExpired is returned when approving a request past its expiry
+	ErrApprovalExpired = errors.New("approval request has expired")
+
+	// ErrAlreadyReversed is returned when a transaction has already been reversed
+	ErrAlreadyReversed = errors.New("transaction has already been reversed")
+
+	// ErrReverseReversal is returned when asked to reverse a reversal
+	ErrReverseReversal = errors.New("a reversal cannot be reversed")
+)
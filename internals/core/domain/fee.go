@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+)
+
+// FeeType defines how a fee schedule prices a transaction
+type FeeType string
+
+const (
+	// FLAT_FEE charges the same amount whatever the transaction size
+	FLAT_FEE FeeType = "flat"
+
+	// PERCENTAGE_FEE charges a share of the amount in basis points
+	PERCENTAGE_FEE = "percentage"
+
+	// TIERED_FEE charges by the band the amount falls in
+	TIERED_FEE = "tiered"
+)
+
+// FeeSchedule model prices the fee charged on transactions of a purpose, for wallets of a tier in
+// a currency. An empty tier applies to every tier without a schedule of its own. Amounts are in
+// minor units and a zero min or max fee leaves that cap off
+type FeeSchedule struct {
+	Base
+	Purpose    PurposeType `json:"purpose" gorm:"not null;uniqueIndex:idx_fee_schedules_key"`
+	Tier       Tier        `json:"tier" gorm:"not null;default:'';uniqueIndex:idx_fee_schedules_key"`
+	Currency   Currency    `json:"currency" gorm:"type:varchar(3);not null;uniqueIndex:idx_fee_schedules_key"`
+	Type       FeeType     `json:"type" gorm:"not null"`
+	FlatAmount int64       `json:"flat_amount" gorm:"not null;default:0"`
+	RateBps    int64       `json:"rate_bps" gorm:"not null;default:0"`
+	MinFee     int64       `json:"min_fee" gorm:"not null;default:0"`
+	MaxFee     int64       `json:"max_fee" gorm:"not null;default:0"`
+	Bands      []FeeBand   `json:"bands,omitempty" gorm:"foreignKey:FeeScheduleID"`
+}
+
+// FeeBand model prices amounts up to UpTo within a tiered schedule, a zero UpTo has no ceiling
+type FeeBand struct {
+	Base
+	FeeScheduleID uuid.UUID `json:"fee_schedule_id" gorm:"type:uuid;not null;index"`
+	UpTo          int64     `json:"up_to" gorm:"not null;default:0"`
+	FlatAmount    int64     `json:"flat_amount" gorm:"not null;default:0"`
+	RateBps       int64     `json:"rate_bps" gorm:"not null;default:0"`
+}
+
+// Compute returns the fee on amount, with percentages rounded half up to the minor unit and the
+// result held between the schedule's min and max fee
+func (s *FeeSchedule) Compute(amount int64) int64 {
+	var fee int64
+	switch s.Type {
+	case FLAT_FEE:
+		fee = s.FlatAmount
+	case PERCENTAGE_FEE:
+		fee = share(amount, s.RateBps)
+	case TIERED_FEE:
+		for _, band := range s.Bands {
+			if band.UpTo == 0 || amount <= band.UpTo {
+				fee = band.FlatAmount + share(amount, band.RateBps)
+				break
+			}
+		}
+	}
+
+	if s.MinFee > 0 && fee < s.MinFee {
+		fee = s.MinFee
+	}
+	if s.MaxFee > 0 && fee > s.MaxFee {
+		fee = s.MaxFee
+	}
+	return fee
+}
+
+func share(amount, bps int64) int64 {
+	return (amount*bps + 5000) / 10000
+}
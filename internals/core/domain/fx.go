@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"time"
+)
+
+// FXQuote model locks a conversion rate between two currencies for a short time
+type FXQuote struct {
+	Base
+	SourceCurrency      Currency   `json:"source_currency" gorm:"type:varchar(3);not null"`
+	DestinationCurrency Currency   `json:"destination_currency" gorm:"type:varchar(3);not null"`
+	MidRate             string     `json:"mid_rate" gorm:"not null"`
+	Rate                string     `json:"rate" gorm:"not null"`
+	SpreadBps           int64      `json:"spread_bps" gorm:"not null"`
+	SourceAmount        int64      `json:"source_amount" gorm:"not null"`
+	DestinationAmount   int64      `json:"destination_amount" gorm:"not null"`
+	ExpiresAt           time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt              *time.Time `json:"used_at,omitempty"`
+}
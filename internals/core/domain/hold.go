@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// HoldStatus defines the state of a hold
+type HoldStatus string
+
+const (
+	// HOLD_ACTIVE a hold that is still reserving funds
+	HOLD_ACTIVE HoldStatus = "active"
+
+	// HOLD_CAPTURED a hold that settled into a debit
+	HOLD_CAPTURED = "captured"
+
+	// HOLD_RELEASED a hold that was cancelled and gave its funds back
+	HOLD_RELEASED = "released"
+
+	// HOLD_EXPIRED a hold that lapsed before it was captured
+	HOLD_EXPIRED = "expired"
+)
+
+// Hold model reserves part of a wallet's balance until it is captured, released or expires
+type Hold struct {
+	Base
+	WalletID       uuid.UUID  `json:"wallet_id" gorm:"type:uuid;not null;index"`
+	Amount         int64      `json:"amount" gorm:"not null"`
+	CapturedAmount int64      `json:"captured_amount" gorm:"not null;default:0"`
+	Currency       Currency   `json:"currency" gorm:"type:varchar(3);not null"`
+	Status         HoldStatus `json:"status" gorm:"not null;index"`
+	Reference      string     `json:"reference,omitempty" gorm:"index"`
+	ExpiresAt      time.Time  `json:"expires_at" gorm:"not null;index"`
+	TransactionID  *uuid.UUID `json:"transaction_id,omitempty" gorm:"type:uuid"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// IdempotencyKey model records the transaction produced for a client supplied key. Keys are
+// scoped to the wallet the transaction was made against, two wallets may use the same key
+type IdempotencyKey struct {
+	Base
+	WalletID      uuid.UUID `json:"wallet_id" gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_keys_wallet_key"`
+	Key           string    `json:"key" gorm:"not null;uniqueIndex:idx_idempotency_keys_wallet_key"`
+	RequestHash   string    `json:"request_hash" gorm:"not null"`
+	TransactionID uuid.UUID `json:"transaction_id" gorm:"type:uuid;not null"`
+	ExpiresAt     time.Time `json:"expires_at" gorm:"not null;index"`
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// ProductType defines the product a wallet is opened as
+type ProductType string
+
+const (
+	// STANDARD_PRODUCT a wallet that earns no interest
+	STANDARD_PRODUCT ProductType = "standard"
+
+	// SAVINGS_PRODUCT a wallet that earns interest on its end of day balance
+	SAVINGS_PRODUCT ProductType = "savings"
+)
+
+// PayoutCycle defines how often accrued interest is paid into a savings wallet
+type PayoutCycle string
+
+const (
+	// DAILY_PAYOUT pays interest at the end of every day
+	DAILY_PAYOUT PayoutCycle = "daily"
+
+	// WEEKLY_PAYOUT pays interest at the end of every Sunday
+	WEEKLY_PAYOUT PayoutCycle = "weekly"
+
+	// MONTHLY_PAYOUT pays interest at the end of the last day of every month
+	MONTHLY_PAYOUT PayoutCycle = "monthly"
+)
+
+// Valid reports whether the cycle is one interest can be paid on
+func (c PayoutCycle) Valid() bool {
+	return c == DAILY_PAYOUT || c == WEEKLY_PAYOUT || c == MONTHLY_PAYOUT
+}
+
+// Closes reports whether day is the last day of a payout cycle
+func (c PayoutCycle) Closes(day time.Time) bool {
+	switch c {
+	case DAILY_PAYOUT:
+		return true
+	case WEEKLY_PAYOUT:
+		return day.Weekday() == time.Sunday
+	default:
+		return day.AddDate(0, 0, 1).Day() == 1
+	}
+}
+
+// DaysInYear is the day count interest accrues over, an actual/365 fixed convention
+const DaysInYear = 365
+
+// DailyInterest returns a day's interest in minor units on balance at an annual rate in basis
+// points, rounding half to even so that halves neither favour the bank nor the customer
+func DailyInterest(balance, annualRateBps int64) int64 {
+	if balance <= 0 || annualRateBps <= 0 {
+		return 0
+	}
+
+	numerator := new(big.Int).Mul(big.NewInt(balance), big.NewInt(annualRateBps))
+	denominator := big.NewInt(10000 * DaysInYear)
+
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+	switch new(big.Int).Mul(remainder, big.NewInt(2)).Cmp(denominator) {
+	case 1:
+		quotient.Add(quotient, big.NewInt(1))
+	case 0:
+		if quotient.Bit(0) == 1 {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient.Int64()
+}
+
+// InterestAccrual model records the interest a savings wallet earned on one day's closing balance
+type InterestAccrual struct {
+	Base
+	WalletID uuid.UUID `json:"wallet_id" gorm:"type:uuid;not null;uniqueIndex:idx_interest_accruals_day"`
+	Day      time.Time `json:"day" gorm:"not null;uniqueIndex:idx_interest_accruals_day"`
+	Balance  int64     `json:"balance" gorm:"not null"`
+	RateBps  int64     `json:"rate_bps" gorm:"not null"`
+	Amount   int64     `json:"amount" gorm:"not null"`
+	// PaidBy is the interest transaction that paid out the cycle this day closed, if it closed one
+	PaidBy *uuid.UUID `json:"paid_by,omitempty" gorm:"type:uuid"`
+}
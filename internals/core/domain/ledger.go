@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+)
+
+// AccountType defines the class of a general ledger account
+type AccountType string
+
+const (
+	// ASSET accounts hold what the business owns, such as funds at the settlement bank
+	ASSET AccountType = "asset"
+
+	// LIABILITY accounts hold what the business owes, every wallet balance is one
+	LIABILITY = "liability"
+
+	// REVENUE accounts collect income such as fees
+	REVENUE = "revenue"
+
+	// EXPENSE accounts collect costs such as interest paid out
+	EXPENSE = "expense"
+)
+
+const (
+	// SETTLEMENT_ACCOUNT is the asset account money enters and leaves the engine through
+	SETTLEMENT_ACCOUNT = "1000"
+
+	// WALLET_ACCOUNT prefixes the liability account of every wallet, followed by its account number
+	WALLET_ACCOUNT = "2000"
+
+	// TRANSFER_CLEARING_ACCOUNT is the liability account both legs of a transfer pass through
+	TRANSFER_CLEARING_ACCOUNT = "2100"
+
+	// FEE_REVENUE_ACCOUNT is the revenue account fees are earned into
+	FEE_REVENUE_ACCOUNT = "4000"
+
+	// INTEREST_EXPENSE_ACCOUNT is the expense account interest is paid out of
+	INTEREST_EXPENSE_ACCOUNT = "5000"
+
+	// ADJUSTMENT_ACCOUNT is the expense account manual balance adjustments are booked against
+	ADJUSTMENT_ACCOUNT = "5100"
+)
+
+// ChartOfAccounts lists the internal accounts opened in every supported currency
+var ChartOfAccounts = []LedgerAccount{
+	{Code: SETTLEMENT_ACCOUNT, Name: "Settlement", Type: ASSET},
+	{Code: TRANSFER_CLEARING_ACCOUNT, Name: "Transfer clearing", Type: LIABILITY},
+	{Code: FEE_REVENUE_ACCOUNT, Name: "Fee revenue", Type: REVENUE},
+	{Code: INTEREST_EXPENSE_ACCOUNT, Name: "Interest expense", Type: EXPENSE},
+	{Code: ADJUSTMENT_ACCOUNT, Name: "Manual adjustments", Type: EXPENSE},
+}
+
+// contraAccounts names the internal account on the other side of a wallet posting, by purpose
+var contraAccounts = map[PurposeType]string{
+	DEPOSIT:    SETTLEMENT_ACCOUNT,
+	WITHDRAWAL: SETTLEMENT_ACCOUNT,
+	CAPTURE:    SETTLEMENT_ACCOUNT,
+	TRANSFER:   TRANSFER_CLEARING_ACCOUNT,
+	FEE:        FEE_REVENUE_ACCOUNT,
+	INTEREST:   INTEREST_EXPENSE_ACCOUNT,
+	ADJUSTMENT: ADJUSTMENT_ACCOUNT,
+}
+
+// ContraAccount returns the code of the internal account a wallet posting with purpose balances
+// against, settlement when the purpose has none of its own
+func ContraAccount(purpose PurposeType) string {
+	if code, ok := contraAccounts[purpose]; ok {
+		return code
+	}
+	return SETTLEMENT_ACCOUNT
+}
+
+// LedgerAccount model is an account in the general ledger, kept in a single currency
+type LedgerAccount struct {
+	Base
+	Code     string      `json:"code" gorm:"not null;uniqueIndex:idx_ledger_accounts_code_currency"`
+	Name     string      `json:"name" gorm:"not null"`
+	Type     AccountType `json:"type" gorm:"not null"`
+	Currency Currency    `json:"currency" gorm:"type:varchar(3);not null;uniqueIndex:idx_ledger_accounts_code_currency"`
+	WalletID *uuid.UUID  `json:"wallet_id,omitempty" gorm:"type:uuid;uniqueIndex"`
+}
+
+// JournalEntry model groups the postings of one business event, they always sum to zero per currency
+type JournalEntry struct {
+	Base
+	Reference     string     `json:"reference,omitempty" gorm:"index"`
+	Description   string     `json:"description"`
+	TransactionID *uuid.UUID `json:"transaction_id,omitempty" gorm:"type:uuid;index"`
+	Postings      []Posting  `json:"postings" gorm:"foreignKey:JournalEntryID"`
+}
+
+// Posting model moves an amount on one ledger account. Debits are positive and credits negative
+type Posting struct {
+	Base
+	JournalEntryID  uuid.UUID `json:"journal_entry_id" gorm:"type:uuid;not null;index"`
+	LedgerAccountID uuid.UUID `json:"ledger_account_id" gorm:"type:uuid;not null;index"`
+	Amount          int64     `json:"amount" gorm:"not null"`
+	Currency        Currency  `json:"currency" gorm:"type:varchar(3);not null"`
+}
+
+// Balanced reports whether the entry's postings sum to zero in every currency
+func (e *JournalEntry) Balanced() bool {
+	totals := map[Currency]int64{}
+	for _, posting := range e.Postings {
+		totals[posting.Currency] += posting.Amount
+	}
+	for _, total := range totals {
+		if total != 0 {
+			return false
+		}
+	}
+	return len(e.Postings) > 0
+}
@@ -0,0 +1,34 @@
+package domain
+
+// Tier defines the limit tier a wallet belongs to
+type Tier string
+
+const (
+	// TIER_ONE the entry tier every wallet starts on
+	TIER_ONE Tier = "tier1"
+
+	// TIER_TWO tier for wallets with partial verification
+	TIER_TWO = "tier2"
+
+	// TIER_THREE tier for fully verified wallets
+	TIER_THREE = "tier3"
+)
+
+// TierLimit model caps what wallets on a tier may move. Amounts are in minor units of the
+// wallet's currency and a zero value leaves that rule switched off
+type TierLimit struct {
+	Base
+	Tier                 Tier  `json:"tier" gorm:"not null;uniqueIndex"`
+	MaxSingleTransaction int64 `json:"max_single_transaction" gorm:"not null;default:0"`
+	DailyDebitLimit      int64 `json:"daily_debit_limit" gorm:"not null;default:0"`
+	MonthlyDebitLimit    int64 `json:"monthly_debit_limit" gorm:"not null;default:0"`
+	MaxTransactions      int64 `json:"max_transactions" gorm:"not null;default:0"`
+	WindowSeconds        int64 `json:"window_seconds" gorm:"not null;default:0"`
+}
+
+// DefaultTierLimits are seeded into an empty database
+var DefaultTierLimits = []TierLimit{
+	{Tier: TIER_ONE, MaxSingleTransaction: 5000000, DailyDebitLimit: 5000000, MonthlyDebitLimit: 30000000, MaxTransactions: 20, WindowSeconds: 3600},
+	{Tier: TIER_TWO, MaxSingleTransaction: 10000000, DailyDebitLimit: 20000000, MonthlyDebitLimit: 100000000, MaxTransactions: 50, WindowSeconds: 3600},
+	{Tier: TIER_THREE, MaxSingleTransaction: 100000000, DailyDebitLimit: 500000000, MaxTransactions: 100, WindowSeconds: 3600},
+}
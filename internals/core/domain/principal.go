@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"github.com/satori/go.uuid"
+)
+
+const (
+	// ADMIN_SCOPE is the token scope that lifts every ownership check
+	ADMIN_SCOPE = "admin"
+
+	// WALLET_READ_SCOPE lets a service read wallets and their history
+	WALLET_READ_SCOPE = "wallet:read"
+
+	// WALLET_WRITE_SCOPE lets a service open, close and remove wallets
+	WALLET_WRITE_SCOPE = "wallet:write"
+
+	// TRANSACTION_WRITE_SCOPE lets a service move funds
+	TRANSACTION_WRITE_SCOPE = "transaction:write"
+
+	// WALLET_ADMIN_SCOPE lets a service do everything an admin can
+	WALLET_ADMIN_SCOPE = "wallet:admin"
+)
+
+// Role defines what a member of staff may do through the admin API
+type Role string
+
+const (
+	// VIEWER_ROLE may look up any wallet, account or audit record
+	VIEWER_ROLE Role = "viewer"
+
+	// OPERATOR_ROLE may also freeze and unfreeze wallets and make small adjustments
+	OPERATOR_ROLE Role = "operator"
+
+	// SUPERVISOR_ROLE may also change any wallet status and make adjustments of any size
+	SUPERVISOR_ROLE Role = "supervisor"
+)
+
+// roleRanks orders the roles, each one may do everything the roles below it may
+var roleRanks = map[Role]int{VIEWER_ROLE: 1, OPERATOR_ROLE: 2, SUPERVISOR_ROLE: 3}
+
+// AtLeast reports whether the role may do everything min may
+func (r Role) AtLeast(min Role) bool {
+	return roleRanks[r] >= roleRanks[min] && roleRanks[r] > 0
+}
+
+// Scopes lists every scope an API key can be granted
+var Scopes = []string{WALLET_READ_SCOPE, WALLET_WRITE_SCOPE, TRANSACTION_WRITE_SCOPE, WALLET_ADMIN_SCOPE}
+
+// Principal is the authenticated caller a request acts for. A customer's Subject is their
+// customer ID. A service, calling with an API key, acts on any customer's wallets but only
+// within the scopes its key was granted
+type Principal struct {
+	Subject string   `json:"subject"`
+	Scopes  []string `json:"scopes"`
+	Service bool     `json:"service"`
+	Role    Role     `json:"role,omitempty"`
+}
+
+// StaffRole returns the role the principal acts in through the admin API. An admin without a
+// role of its own is treated as a supervisor, anyone else without one is not staff
+func (p *Principal) StaffRole() Role {
+	if p.Role == "" && p.IsAdmin() {
+		return SUPERVISOR_ROLE
+	}
+	return p.Role
+}
+
+// HasScope reports whether the principal was granted scope
+func (p *Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsAdmin reports whether the principal may act on every customer's wallets
+func (p *Principal) IsAdmin() bool {
+	return p.HasScope(ADMIN_SCOPE) || p.HasScope(WALLET_ADMIN_SCOPE)
+}
+
+// Allows reports whether the principal may use a capability. Customers are limited by ownership
+// instead, so only a service needs the scope. Ownership cannot limit credits, see MayCredit
+func (p *Principal) Allows(scope string) bool {
+	return !p.Service || p.IsAdmin() || p.HasScope(scope)
+}
+
+// MayCredit reports whether the principal may credit a wallet with funds from outside the engine.
+// A customer's funds arrive through a service or staff, a customer only moves what they hold
+func (p *Principal) MayCredit() bool {
+	return p.Service || p.IsAdmin()
+}
+
+// Owns reports whether the principal may act on what owner holds
+func (p *Principal) Owns(owner uuid.UUID) bool {
+	return p.IsAdmin() || p.Service || p.Subject == owner.String()
+}
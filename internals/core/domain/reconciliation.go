@@ -0,0 +1,24 @@
+package domain
+
+// DiscrepancyKind defines what a reconciliation found wrong with a wallet
+type DiscrepancyKind string
+
+const (
+	// BROKEN_CHAIN a transaction whose balance before is not the previous transaction's balance after
+	BROKEN_CHAIN DiscrepancyKind = "broken_chain"
+
+	// GAP a history whose first transaction does not start from a zero balance
+	GAP = "gap"
+
+	// ENTRY_MISMATCH a transaction whose balance after is not its balance before moved by its amount
+	ENTRY_MISMATCH = "entry_mismatch"
+
+	// BALANCE_MISMATCH a wallet balance that differs from its last transaction's balance after
+	BALANCE_MISMATCH = "balance_mismatch"
+
+	// JOURNAL_MISMATCH a wallet balance that differs from its credits less its debits
+	JOURNAL_MISMATCH = "journal_mismatch"
+
+	// TAMPERED the first transaction whose hash no longer matches its contents or its predecessor
+	TAMPERED = "tampered"
+)
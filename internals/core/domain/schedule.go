@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// ScheduleStatus defines the state of a scheduled transaction
+type ScheduleStatus string
+
+const (
+	// SCHEDULE_ACTIVE a schedule that still runs
+	SCHEDULE_ACTIVE ScheduleStatus = "active"
+
+	// SCHEDULE_CANCELLED a schedule stopped by its owner
+	SCHEDULE_CANCELLED ScheduleStatus = "cancelled"
+
+	// SCHEDULE_COMPLETED a schedule that passed its end
+	SCHEDULE_COMPLETED ScheduleStatus = "completed"
+)
+
+// ExecutionStatus defines the outcome of one run of a scheduled transaction
+type ExecutionStatus string
+
+const (
+	// EXECUTION_SUCCEEDED a run that posted its transaction
+	EXECUTION_SUCCEEDED ExecutionStatus = "succeeded"
+
+	// EXECUTION_FAILED a run the wallet engine refused, its error says why
+	EXECUTION_FAILED ExecutionStatus = "failed"
+
+	// EXECUTION_PENDING_APPROVAL a run whose debit was queued for approval instead of posted
+	EXECUTION_PENDING_APPROVAL ExecutionStatus = "pending_approval"
+)
+
+// ScheduledTransaction model is a standing order, run either on a five field cron expression or
+// every IntervalSeconds. With a destination it moves Amount from the wallet to the destination,
+// otherwise it credits or debits the wallet itself
+type ScheduledTransaction struct {
+	Base
+	WalletID            uuid.UUID            `json:"wallet_id" gorm:"type:uuid;not null;index"`
+	DestinationWalletID *uuid.UUID           `json:"destination_wallet_id,omitempty" gorm:"type:uuid"`
+	TransactionType     TxnType              `json:"transaction_type,omitempty"`
+	Purpose             PurposeType          `json:"purpose,omitempty"`
+	Amount              int64                `json:"amount" gorm:"not null"`
+	Currency            Currency             `json:"currency,omitempty" gorm:"type:varchar(3)"`
+	Cron                string               `json:"cron,omitempty"`
+	IntervalSeconds     int64                `json:"interval_seconds,omitempty" gorm:"not null;default:0"`
+	Status              ScheduleStatus       `json:"status" gorm:"not null;index"`
+	NextRunAt           time.Time            `json:"next_run_at" gorm:"not null;index"`
+	LastRunAt           *time.Time           `json:"last_run_at,omitempty"`
+	EndsAt              *time.Time           `json:"ends_at,omitempty"`
+	Executions          []ScheduledExecution `json:"executions,omitempty" gorm:"foreignKey:ScheduleID"`
+}
+
+// ScheduledExecution model records one run of a scheduled transaction. Its reference is the
+// idempotency key the run posted under, so a run retried after a crash cannot post twice
+type ScheduledExecution struct {
+	Base
+	ScheduleID    uuid.UUID       `json:"schedule_id" gorm:"type:uuid;not null;index"`
+	Occurrence    time.Time       `json:"occurrence" gorm:"not null"`
+	Reference     string          `json:"reference" gorm:"not null;uniqueIndex"`
+	Status        ExecutionStatus `json:"status" gorm:"not null"`
+	TransactionID *uuid.UUID      `json:"transaction_id,omitempty" gorm:"type:uuid"`
+	ApprovalID    *uuid.UUID      `json:"approval_id,omitempty" gorm:"type:uuid"`
+	Error         string          `json:"error,omitempty"`
+}
@@ -1,5 +1,17 @@
 package domain
 
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
 // TxnType defines the transaction type
 type TxnType string
 
@@ -14,6 +26,15 @@ const (
 	DEBIT = "debit"
 )
 
+// ParseTxnType normalises value and checks it is a credit or a debit
+func ParseTxnType(value string) (TxnType, error) {
+	kind := TxnType(strings.ToLower(strings.TrimSpace(value)))
+	if kind != CREDIT && kind != DEBIT {
+		return "", ErrInvalidTransactionType
+	}
+	return kind, nil
+}
+
 const (
 	// DEPOSIT transaction purpose type
 	DEPOSIT PurposeType = "deposit"
@@ -23,6 +44,21 @@ const (
 
 	// REVERSAL transaction purpose type
 	REVERSAL = "reversal"
+
+	// TRANSFER transaction purpose type
+	TRANSFER = "transfer"
+
+	// CAPTURE transaction purpose type
+	CAPTURE = "capture"
+
+	// FEE transaction purpose type
+	FEE = "fee"
+
+	// INTEREST transaction purpose type
+	INTEREST = "interest"
+
+	// ADJUSTMENT transaction purpose type
+	ADJUSTMENT = "adjustment"
 )
 
 // Transaction model
@@ -31,7 +67,84 @@ type Transaction struct {
 	TransactionType TxnType     `json:"transaction_type" gorm:"not null"`
 	Purpose         PurposeType `json:"purpose" gorm:"not null;index"`
 	Amount          int64       `json:"amount" gorm:"not null"`
+	Currency        Currency    `json:"currency" gorm:"type:varchar(3);not null;default:'NGN'"`
 	AccountID       int64       `json:"account_id" gorm:"not null;index"`
+	WalletID        uuid.UUID   `json:"wallet_id" gorm:"type:uuid;index"`
 	BalanceBefore   int64       `json:"balance_before" gorm:"not null"`
 	BalanceAfter    int64       `json:"balance_after" gorm:"not null"`
+	Reference       string      `json:"reference,omitempty" gorm:"index"`
+	ReversalOf      *uuid.UUID  `json:"reversal_of,omitempty" gorm:"type:uuid;uniqueIndex"`
+
+	QuoteID           *uuid.UUID `json:"quote_id,omitempty" gorm:"type:uuid;index"`
+	FXRate            string     `json:"fx_rate,omitempty"`
+	FXSpreadBps       int64      `json:"fx_spread_bps,omitempty"`
+	SourceAmount      int64      `json:"source_amount,omitempty"`
+	DestinationAmount int64      `json:"destination_amount,omitempty"`
+
+	ParentID *uuid.UUID    `json:"parent_id,omitempty" gorm:"type:uuid;index"`
+	Fees     []Transaction `json:"fees,omitempty" gorm:"foreignKey:ParentID"`
+
+	Hash         string `json:"hash,omitempty" gorm:"type:varchar(64);index"`
+	PreviousHash string `json:"previous_hash,omitempty" gorm:"type:varchar(64)"`
+}
+
+// Seal fixes the transaction's id and creation time, then chains it to previousHash, the hash of
+// the wallet's latest transaction, under key. The time is cut to microseconds, the finest
+// precision Postgres keeps, so the hash still matches once the row is read back
+func (t *Transaction) Seal(previousHash string, key []byte) {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.NewV4()
+	}
+	t.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	t.PreviousHash = previousHash
+	t.Hash = t.ComputeHash(key)
+}
+
+// ComputeHash returns the HMAC-SHA256 under key of every field a posting is made of, previous
+// hash included. Without the key a rewritten transaction cannot be given a matching hash
+func (t *Transaction) ComputeHash(key []byte) string {
+	optional := func(id *uuid.UUID) string {
+		if id == nil {
+			return ""
+		}
+		return id.String()
+	}
+
+	fields := []string{
+		t.ID.String(),
+		t.WalletID.String(),
+		fmt.Sprint(t.AccountID),
+		string(t.TransactionType),
+		string(t.Purpose),
+		fmt.Sprint(t.Amount),
+		string(t.Currency),
+		fmt.Sprint(t.BalanceBefore),
+		fmt.Sprint(t.BalanceAfter),
+		t.Reference,
+		optional(t.ReversalOf),
+		optional(t.QuoteID),
+		t.FXRate,
+		fmt.Sprint(t.FXSpreadBps),
+		fmt.Sprint(t.SourceAmount),
+		fmt.Sprint(t.DestinationAmount),
+		optional(t.ParentID),
+		t.CreatedAt.UTC().Format(time.RFC3339Nano),
+		t.PreviousHash,
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.Join(fields, "|")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// BeforeUpdate hooks keep the journal append-only, a posted transaction is corrected by a
+// reversal, never edited. Both hooks take a value receiver so they also run when the model is
+// handed over by value, as Repository.Delete does
+func (t Transaction) BeforeUpdate(tx *gorm.DB) error {
+	return ErrImmutableTransaction
+}
+
+// BeforeDelete hooks keep the journal append-only, soft deletes included
+func (t Transaction) BeforeDelete(tx *gorm.DB) error {
+	return ErrImmutableTransaction
 }
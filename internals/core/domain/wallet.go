@@ -1,25 +1,123 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/satori/go.uuid"
+	"gorm.io/gorm"
 )
 
 // State defines the state of the wallet
 type State string
 
 const (
+	// PENDING state for a wallet that is not open for business yet
+	PENDING State = "pending"
+
 	// ACTIVE state for an active wallet
 	ACTIVE State = "active"
 
 	// INACTIVE state for an inactive wallet
-	INACTIVE = "inactive"
+	INACTIVE State = "inactive"
+
+	// FROZEN state for a wallet that can neither be credited nor debited
+	FROZEN State = "frozen"
+
+	// DEBIT_BLOCKED state for a wallet that can only be credited
+	DEBIT_BLOCKED State = "debit_blocked"
+
+	// CREDIT_BLOCKED state for a wallet that can only be debited
+	CREDIT_BLOCKED State = "credit_blocked"
+
+	// CLOSED state for a wallet that is permanently shut
+	CLOSED State = "closed"
 )
 
-// Wallet model
+// transitions lists the states each state may move to
+var transitions = map[State][]State{
+	PENDING:        {ACTIVE, CLOSED},
+	ACTIVE:         {INACTIVE, FROZEN, DEBIT_BLOCKED, CREDIT_BLOCKED, CLOSED},
+	INACTIVE:       {ACTIVE, FROZEN, CLOSED},
+	FROZEN:         {ACTIVE, CLOSED},
+	DEBIT_BLOCKED:  {ACTIVE, FROZEN, CLOSED},
+	CREDIT_BLOCKED: {ACTIVE, FROZEN, CLOSED},
+	CLOSED:         {},
+}
+
+// Valid reports whether the state is part of the wallet lifecycle
+func (s State) Valid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether a wallet in this state may move to next
+func (s State) CanTransitionTo(next State) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsCredit reports whether a wallet in this state may receive funds
+func (s State) AllowsCredit() bool {
+	return s == ACTIVE || s == DEBIT_BLOCKED
+}
+
+// AllowsDebit reports whether a wallet in this state may send funds
+func (s State) AllowsDebit() bool {
+	return s == ACTIVE || s == CREDIT_BLOCKED
+}
+
+// WalletStatusChange model records who moved a wallet between states and why
+type WalletStatusChange struct {
+	Base
+	WalletID uuid.UUID `json:"wallet_id" gorm:"type:uuid;not null;index"`
+	From     State     `json:"from" gorm:"not null"`
+	To       State     `json:"to" gorm:"not null"`
+	Reason   string    `json:"reason"`
+	Actor    string    `json:"actor" gorm:"not null"`
+}
+
+// Wallet model. Balance is the ledger balance, HeldBalance the part of it reserved by
+// active holds and AvailableBalance what is left to spend. A savings wallet earns
+// AnnualInterestRateBps on its closing balance each day, AccruedInterest holding what it has
+// earned since the last payout and InterestAccruedOn the last day accrued. ChainHead is the hash
+// of the wallet's latest transaction, so a chain cut short at its end is caught
 type Wallet struct {
 	Base
-	Owner     uuid.UUID `json:"owner," gorm:"not null;index"`
-	Balance   int64     `json:"balance" gorm:"not null"`
-	Status    State     `json:"status" gorm:"index"`
-	AccountID int64     `json:"account_id" gorm:"index"`
+	Owner            uuid.UUID `json:"owner," gorm:"not null;index"`
+	Balance          int64     `json:"balance" gorm:"not null"`
+	HeldBalance      int64     `json:"held_balance" gorm:"not null;default:0"`
+	AvailableBalance int64     `json:"available_balance" gorm:"-"`
+	Currency         Currency  `json:"currency" gorm:"type:varchar(3);not null;default:'NGN'"`
+	Status           State     `json:"status" gorm:"index"`
+	Tier             Tier      `json:"tier" gorm:"not null;default:'tier1'"`
+	AccountID        int64     `json:"account_id" gorm:"uniqueIndex:idx_wallets_account_number"`
+	ChainHead        string    `json:"chain_head,omitempty" gorm:"type:varchar(64);not null;default:''"`
+
+	ProductType           ProductType `json:"product_type" gorm:"not null;default:'standard';index"`
+	AnnualInterestRateBps int64       `json:"annual_interest_rate_bps" gorm:"not null;default:0"`
+	AccruedInterest       int64       `json:"accrued_interest" gorm:"not null;default:0"`
+	InterestAccruedOn     *time.Time  `json:"interest_accrued_on,omitempty"`
+
+	Transactions []Transaction `json:"transactions,omitempty" gorm:"foreignKey:WalletID"`
+}
+
+// Available returns the part of the ledger balance that is not reserved by holds
+func (w *Wallet) Available() int64 {
+	return w.Balance - w.HeldBalance
+}
+
+// AfterFind hooks run after a wallet is loaded to derive its available balance
+func (w *Wallet) AfterFind(tx *gorm.DB) (err error) {
+	w.AvailableBalance = w.Available()
+	return
+}
+
+// AfterSave hooks run after a wallet is written to keep its available balance current
+func (w *Wallet) AfterSave(tx *gorm.DB) (err error) {
+	w.AvailableBalance = w.Available()
+	return
 }
@@ -0,0 +1,7 @@
+package ports
+
+// IAccountNumberGenerator defines the interface for allocating wallet account numbers
+type IAccountNumberGenerator interface {
+	Generate() (int64, error)
+	Valid(accountNumber int64) bool
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/pkg/utils"
+)
+
+// IAdminService defines the interface for the staff admin service
+type IAdminService interface {
+	ChangeStatus(params common.GetByIDRequest, next domain.State, reason string, actor *domain.Principal) (*domain.Wallet, error)
+	Adjust(params common.GetByIDRequest, body common.AdjustmentRequest, actor *domain.Principal) (*domain.Transaction, error)
+	GetAuditLogs(filter common.AuditLogFilterRequest, pagination *utils.Pagination) (*utils.Pagination, error)
+}
+
+// IAdminHandler defines the interface for the staff admin handler
+type IAdminHandler interface {
+	FreezeWallet(c *gin.Context)
+	UnfreezeWallet(c *gin.Context)
+	ChangeStatus(c *gin.Context)
+	AdjustBalance(c *gin.Context)
+	GetAuditLogs(c *gin.Context)
+}
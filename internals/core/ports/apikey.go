@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+)
+
+// IAPIKeyService defines the interface for an API key service
+type IAPIKeyService interface {
+	CreateAPIKey(body common.CreateAPIKeyRequest, actor string) (*common.IssuedAPIKey, error)
+	GetAPIKeys() ([]domain.APIKey, error)
+	RevokeAPIKey(params common.GetByIDRequest) (*domain.APIKey, error)
+	Authenticate(key string) (*domain.APIKey, error)
+}
+
+// IAPIKeyHandler defines the interface for API key handler
+type IAPIKeyHandler interface {
+	CreateAPIKey(c *gin.Context)
+	GetAPIKeys(c *gin.Context)
+	RevokeAPIKey(c *gin.Context)
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/pkg/utils"
+)
+
+// IApprovalService defines the interface for the maker-checker approval service
+type IApprovalService interface {
+	GetApproval(id string) (*domain.ApprovalRequest, error)
+	GetApprovals(filter common.ApprovalFilterRequest, pagination *utils.Pagination) (*utils.Pagination, error)
+	Approve(params common.GetByIDRequest, checker *domain.Principal) (*domain.ApprovalRequest, *domain.Transaction, error)
+	Reject(params common.GetByIDRequest, reason string, checker *domain.Principal) (*domain.ApprovalRequest, error)
+	ExpireApprovals() (int, error)
+}
+
+// IApprovalHandler defines the interface for approval handler
+type IApprovalHandler interface {
+	GetApproval(c *gin.Context)
+	GetApprovals(c *gin.Context)
+	Approve(c *gin.Context)
+	Reject(c *gin.Context)
+}
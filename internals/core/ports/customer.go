@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/pkg/utils"
+)
+
+// ICustomerService defines the interface for a customer service
+type ICustomerService interface {
+	CreateCustomer(body common.CreateCustomerRequest) (*domain.Customer, error)
+	GetCustomers(pagination *utils.Pagination) (*utils.Pagination, error)
+	GetCustomer(params common.GetByIDRequest) (*domain.Customer, error)
+	UpdateCustomer(params common.GetByIDRequest, body common.UpdateCustomerRequest) (*domain.Customer, error)
+	DeleteCustomer(params common.GetByIDRequest) error
+	GetCustomerWallets(params common.GetByIDRequest) ([]domain.Wallet, error)
+}
+
+// ICustomerHandler defines the interface for customer handler
+type ICustomerHandler interface {
+	CreateCustomer(c *gin.Context)
+	GetCustomers(c *gin.Context)
+	GetCustomer(c *gin.Context)
+	UpdateCustomer(c *gin.Context)
+	DeleteCustomer(c *gin.Context)
+	GetCustomerWallets(c *gin.Context)
+}
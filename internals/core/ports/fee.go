@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+)
+
+// IFeeService defines the interface for a fee schedule service
+type IFeeService interface {
+	GetFeeSchedules() ([]domain.FeeSchedule, error)
+	SaveFeeSchedule(body common.SaveFeeScheduleRequest) (*domain.FeeSchedule, error)
+	DeleteFeeSchedule(id string) error
+}
+
+// IFeeHandler defines the interface for fee schedule handler
+type IFeeHandler interface {
+	GetFeeSchedules(c *gin.Context)
+	SaveFeeSchedule(c *gin.Context)
+	DeleteFeeSchedule(c *gin.Context)
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"math/big"
+
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+)
+
+// IFXRateProvider defines the interface for a source of mid market exchange rates
+type IFXRateProvider interface {
+	Rate(from, to domain.Currency) (*big.Rat, error)
+}
+
+// IFXService defines the interface for a foreign exchange service
+type IFXService interface {
+	CreateQuote(body common.CreateQuoteRequest) (*domain.FXQuote, error)
+}
+
+// IFXHandler defines the interface for foreign exchange handler
+type IFXHandler interface {
+	CreateQuote(c *gin.Context)
+}
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+)
+
+// IHoldService defines the interface for a hold service
+type IHoldService interface {
+	GetHoldByID(id string) (*domain.Hold, error)
+	CreateHold(params common.GetByIDRequest, body common.CreateHoldRequest, actor string) (*domain.Hold, error)
+	CaptureHold(params common.GetByIDRequest, body common.CaptureHoldRequest) (*domain.Hold, *domain.Transaction, error)
+	ReleaseHold(params common.GetByIDRequest) (*domain.Hold, error)
+	ExpireHolds() (int, error)
+}
+
+// IHoldHandler defines the interface for hold handler
+type IHoldHandler interface {
+	GetHoldByID(c *gin.Context)
+	CreateHold(c *gin.Context)
+	CaptureHold(c *gin.Context)
+	ReleaseHold(c *gin.Context)
+}
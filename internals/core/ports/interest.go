@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+)
+
+// IInterestService defines the interface for an interest accrual service
+type IInterestService interface {
+	AccrueInterest(asOf time.Time) (int, error)
+	GetAccruals(params common.GetByIDRequest) ([]domain.InterestAccrual, error)
+}
+
+// IInterestHandler defines the interface for interest handler
+type IInterestHandler interface {
+	GetAccruals(c *gin.Context)
+}
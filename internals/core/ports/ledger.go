@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+)
+
+// ILedgerService defines the interface for a general ledger service
+type ILedgerService interface {
+	GetAccounts(filter common.LedgerAccountFilterRequest) ([]domain.LedgerAccount, error)
+	GetTrialBalance() (*common.TrialBalance, error)
+}
+
+// ILedgerHandler defines the interface for general ledger handler
+type ILedgerHandler interface {
+	GetAccounts(c *gin.Context)
+	GetTrialBalance(c *gin.Context)
+}
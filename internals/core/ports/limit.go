@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+)
+
+// ILimitService defines the interface for a tier limit service
+type ILimitService interface {
+	GetLimits() ([]domain.TierLimit, error)
+	UpdateLimit(params common.GetByTierRequest, body common.UpdateTierLimitRequest) (*domain.TierLimit, error)
+}
+
+// ILimitHandler defines the interface for tier limit handler
+type ILimitHandler interface {
+	GetLimits(c *gin.Context)
+	UpdateLimit(c *gin.Context)
+}
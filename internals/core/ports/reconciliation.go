@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+)
+
+// IReconciliationService defines the interface for a reconciliation service
+type IReconciliationService interface {
+	Reconcile() (*common.ReconciliationReport, error)
+	WriteReport(report *common.ReconciliationReport, dir string) ([]string, error)
+	VerifyChain(params common.GetByIDRequest) (*common.ChainVerification, error)
+}
+
+// IReconciliationHandler defines the interface for reconciliation handler
+type IReconciliationHandler interface {
+	VerifyChain(c *gin.Context)
+}
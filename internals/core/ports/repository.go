@@ -6,5 +6,5 @@ import (
 
 // RequestDTO declaring input DTO
 type RequestDTO interface {
-	domain.Wallet | domain.Transaction
+	domain.Wallet | domain.Transaction | domain.IdempotencyKey | domain.FXQuote | domain.Hold | domain.TierLimit | domain.WalletStatusChange | domain.LedgerAccount | domain.JournalEntry | domain.Posting | domain.FeeSchedule | domain.FeeBand | domain.ScheduledTransaction | domain.ScheduledExecution | domain.InterestAccrual | domain.Customer | domain.APIKey | domain.AuditLog | domain.ApprovalRequest
 }
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+)
+
+// IScheduleService defines the interface for a scheduled transaction service
+type IScheduleService interface {
+	CreateSchedule(body common.CreateScheduleRequest) (*domain.ScheduledTransaction, error)
+	GetSchedule(params common.GetByIDRequest) (*domain.ScheduledTransaction, error)
+	GetWalletSchedules(params common.GetByIDRequest) ([]domain.ScheduledTransaction, error)
+	CancelSchedule(params common.GetByIDRequest) (*domain.ScheduledTransaction, error)
+	RunDueSchedules() (int, error)
+}
+
+// IScheduleHandler defines the interface for scheduled transaction handler
+type IScheduleHandler interface {
+	CreateSchedule(c *gin.Context)
+	GetSchedule(c *gin.Context)
+	GetWalletSchedules(c *gin.Context)
+	CancelSchedule(c *gin.Context)
+}
@@ -2,24 +2,46 @@ package ports
 
 import (
 	"github.com/gin-gonic/gin"
+
 	"wallet_engine/internals/common"
 	"wallet_engine/internals/core/domain"
+	"wallet_engine/pkg/utils"
 )
 
 // IWalletService defines the interface for a wallet service
 type IWalletService interface {
 	GetWalletByID(id string) (*domain.Wallet, error)
+	GetWalletWithTransactions(id string) (*domain.Wallet, error)
+	GetWalletByAccountNumber(accountNumber int64) (*domain.Wallet, error)
 	CreateWallet(wallet *domain.Wallet) error
 	UpdateWallet(params common.GetByIDRequest, state common.UpdateWalletRequest) (*domain.Wallet, error)
-	CreateTransaction(params common.GetByIDRequest, transaction common.CreateTransactionRequest) (*domain.Transaction, error)
+	GetStatusChanges(params common.GetByIDRequest) ([]domain.WalletStatusChange, error)
+	CloseWallet(params common.GetByIDRequest, body common.CloseWalletRequest) (*domain.Wallet, *common.TransferResponse, error)
+	GetWallets(filter common.WalletFilterRequest, pagination *utils.Pagination) (*utils.Pagination, error)
+	RestoreWallet(id string) (*domain.Wallet, error)
+	PurgeWallets() (int, error)
+	DeriveBalance(params common.GetByIDRequest) (*common.LedgerBalance, error)
+	CreateTransaction(params common.GetByIDRequest, transaction common.CreateTransactionRequest, actor string) (*domain.Transaction, error)
 	DeleteWallet(id string) error
+	Transfer(body common.CreateTransferRequest, actor string) (*domain.Transaction, *domain.Transaction, error)
+	ReverseTransaction(params common.GetByIDRequest) (*domain.Transaction, error)
+	GetTransactions(params common.GetByIDRequest, filter common.TransactionFilterRequest, pagination *utils.Pagination) (*utils.Pagination, error)
 }
 
 // IWalletHandler defines the interface for wallet handler
 type IWalletHandler interface {
 	GetWalletByID(c *gin.Context)
+	GetWalletByAccountNumber(c *gin.Context)
 	CreateWallet(c *gin.Context)
 	DeleteWallet(c *gin.Context)
 	UpdateWallet(c *gin.Context)
+	GetStatusChanges(c *gin.Context)
+	CloseWallet(c *gin.Context)
+	GetWallets(c *gin.Context)
+	RestoreWallet(c *gin.Context)
+	DeriveBalance(c *gin.Context)
 	TransactionWallet(c *gin.Context)
+	Transfer(c *gin.Context)
+	ReverseTransaction(c *gin.Context)
+	GetTransactions(c *gin.Context)
 }
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+	tx "wallet_engine/pkg/unit_of_work"
+	"wallet_engine/pkg/utils"
+)
+
+type adminService struct {
+	WalletRepository    repositories.Repository[domain.Wallet]
+	AuditRepository     repositories.Repository[domain.AuditLog]
+	WalletService       TxWalletService
+	SupervisorThreshold int64
+	logger              *log.Logger
+	db                  *gorm.DB
+}
+
+// NewAdminService function create a new instance for service. Adjustments above threshold, in
+// minor units, need a supervisor
+func NewAdminService(wr repositories.Repository[domain.Wallet], ar repositories.Repository[domain.AuditLog], ws TxWalletService, threshold int64, l *log.Logger, db *gorm.DB) ports.IAdminService {
+	return &adminService{
+		WalletRepository:    wr,
+		AuditRepository:     ar,
+		WalletService:       ws,
+		SupervisorThreshold: threshold,
+		logger:              l,
+		db:                  db,
+	}
+}
+
+// ChangeStatus moves a wallet to next on behalf of a member of staff, recording the change in the
+// wallet's status history and the audit trail together
+func (a *adminService) ChangeStatus(params common.GetByIDRequest, next domain.State, reason string, actor *domain.Principal) (*domain.Wallet, error) {
+	uw := tx.NewGormUnitOfWork(a.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := a.WalletRepository.WithTx(t).GetByIDForUpdate(params.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	from := wallet.Status
+	err = a.WalletService.ChangeStatus(t, wallet, next, reason, actor.Subject)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = audit(a.AuditRepository.WithTx(t), actor, domain.WALLET_STATUS_CHANGED, wallet.ID, reason, map[string]interface{}{
+		"from": from,
+		"to":   next,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return wallet, nil
+}
+
+// Adjust credits or debits a wallet by hand to correct its balance. It is booked against the
+// manual adjustments account, outside the wallet's tier limits and fees, and anything above the
+// supervisor threshold needs a supervisor. A reference is an idempotency key, replaying it returns
+// the original adjustment
+func (a *adminService) Adjust(params common.GetByIDRequest, body common.AdjustmentRequest, actor *domain.Principal) (*domain.Transaction, error) {
+	if body.Amount > a.SupervisorThreshold && !actor.StaffRole().AtLeast(domain.SUPERVISOR_ROLE) {
+		return nil, fmt.Errorf("%w: %d is above %d", domain.ErrSupervisorRequired, body.Amount, a.SupervisorThreshold)
+	}
+
+	hash := requestHash(params.ID, domain.ADJUSTMENT, body.TransactionType, body.Amount)
+
+	transaction, err := a.adjust(params, body, hash, actor)
+	if err != nil && body.Reference != "" {
+		// a concurrent request holding the same key may have committed first
+		if replay, _ := a.WalletService.FindReplay(a.db, params.ID, body.Reference, hash); replay != nil {
+			return replay, nil
+		}
+	}
+	return transaction, err
+}
+
+func (a *adminService) adjust(params common.GetByIDRequest, body common.AdjustmentRequest, hash string, actor *domain.Principal) (*domain.Transaction, error) {
+	reference := body.Reference
+	if reference == "" {
+		reference = fmt.Sprintf("adjustment:%v", uuid.NewV4())
+	}
+
+	uw := tx.NewGormUnitOfWork(a.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if body.Reference != "" {
+		var replay *domain.Transaction
+		replay, err = a.WalletService.FindReplay(t, params.ID, body.Reference, hash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if replay != nil {
+			err = uw.Commit()
+			return replay, err
+		}
+	}
+
+	wallet, err := a.WalletRepository.WithTx(t).GetByIDForUpdate(params.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := a.WalletService.PostTransaction(t, wallet, common.CreateTransactionRequest{
+		TransactionType: body.TransactionType,
+		Purpose:         string(domain.ADJUSTMENT),
+		Amount:          body.Amount,
+		Reference:       reference,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if body.Reference != "" {
+		err = a.WalletService.Remember(t, body.Reference, hash, transaction)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = audit(a.AuditRepository.WithTx(t), actor, domain.WALLET_ADJUSTED, wallet.ID, body.Reason, map[string]interface{}{
+		"transaction_id":   transaction.ID,
+		"transaction_type": transaction.TransactionType,
+		"amount":           transaction.Amount,
+		"balance_before":   transaction.BalanceBefore,
+		"balance_after":    transaction.BalanceAfter,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// audit records through repository that actor took action on a wallet
+func audit(repository *repositories.Repository[domain.AuditLog], actor *domain.Principal, action domain.AuditAction, walletID uuid.UUID, reason string, details map[string]interface{}) error {
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+
+	return repository.Persist(&domain.AuditLog{
+		Actor:        actor.Subject,
+		Role:         actor.StaffRole(),
+		Action:       action,
+		ResourceType: "wallet",
+		ResourceID:   walletID,
+		Reason:       reason,
+		Details:      string(payload),
+	})
+}
+
+func (a *adminService) GetAuditLogs(filter common.AuditLogFilterRequest, pagination *utils.Pagination) (*utils.Pagination, error) {
+	return a.AuditRepository.GetWhere(pagination, func(db *gorm.DB) *gorm.DB {
+		if filter.Actor != "" {
+			db = db.Where("actor = ?", filter.Actor)
+		}
+		if filter.Action != "" {
+			db = db.Where("action = ?", filter.Action)
+		}
+		if filter.ResourceID != "" {
+			db = db.Where("resource_id = ?", filter.ResourceID)
+		}
+		return db
+	})
+}
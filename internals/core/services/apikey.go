@@ -0,0 +1,136 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+)
+
+const (
+	// apiKeyTag starts every API key so a leaked one is easy to recognise
+	apiKeyTag = "wk"
+
+	// apiKeyTouchInterval is how stale a key's last use may get before it is written again, so a
+	// busy key does not cost a write on every request
+	apiKeyTouchInterval = time.Minute
+)
+
+type apiKeyService struct {
+	APIKeyRepository repositories.Repository[domain.APIKey]
+	logger           *log.Logger
+}
+
+// NewAPIKeyService function create a new instance for service
+func NewAPIKeyService(ar repositories.Repository[domain.APIKey], l *log.Logger) ports.IAPIKeyService {
+	return &apiKeyService{
+		APIKeyRepository: ar,
+		logger:           l,
+	}
+}
+
+// CreateAPIKey issues a key of the form wk_<prefix>_<secret>, returning it once. Only its hash
+// is stored
+func (a *apiKeyService) CreateAPIKey(body common.CreateAPIKeyRequest, actor string) (*common.IssuedAPIKey, error) {
+	prefix, err := randomHex(6)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%v_%v_%v", apiKeyTag, prefix, secret)
+
+	apiKey := &domain.APIKey{
+		Name:      body.Name,
+		Prefix:    prefix,
+		Hash:      domain.HashAPIKey(key),
+		Scopes:    strings.Join(body.Scopes, " "),
+		CreatedBy: actor,
+	}
+
+	if err := a.APIKeyRepository.Persist(apiKey); err != nil {
+		a.logger.Error(err)
+		return nil, err
+	}
+	return &common.IssuedAPIKey{APIKey: *apiKey, Key: key}, nil
+}
+
+func (a *apiKeyService) GetAPIKeys() ([]domain.APIKey, error) {
+	keys, err := a.APIKeyRepository.GetAllWhere(func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at desc")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (a *apiKeyService) RevokeAPIKey(params common.GetByIDRequest) (*domain.APIKey, error) {
+	apiKey, err := a.APIKeyRepository.GetByID(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiKey.Revoked() {
+		return nil, domain.ErrAPIKeyRevoked
+	}
+
+	now := time.Now()
+	apiKey.RevokedAt = &now
+	if err := a.APIKeyRepository.Update(apiKey); err != nil {
+		a.logger.Error(err)
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+// Authenticate finds the key by its prefix and checks the rest against the stored hash, recording
+// when it was last used
+func (a *apiKeyService) Authenticate(key string) (*domain.APIKey, error) {
+	parts := strings.Split(key, "_")
+	if len(parts) != 3 || parts[0] != apiKeyTag {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	apiKey, err := a.APIKeyRepository.GetBy("prefix = ?", parts[1])
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !apiKey.Matches(key) || apiKey.Revoked() {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyTouchInterval {
+		apiKey.LastUsedAt = &now
+		if err := a.APIKeyRepository.Update(apiKey); err != nil {
+			a.logger.Warnf("api key %v last use not recorded: %v", apiKey.Prefix, err)
+		}
+	}
+	return apiKey, nil
+}
+
+// randomHex returns n random bytes hex encoded
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
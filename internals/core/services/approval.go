@@ -0,0 +1,477 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+	"wallet_engine/pkg/config"
+	tx "wallet_engine/pkg/unit_of_work"
+	"wallet_engine/pkg/utils"
+)
+
+type approvalService struct {
+	WalletRepository   repositories.Repository[domain.Wallet]
+	ApprovalRepository repositories.Repository[domain.ApprovalRequest]
+	AuditRepository    repositories.Repository[domain.AuditLog]
+	WalletService      TxWalletService
+	HoldService        TxHoldService
+	logger             *log.Logger
+	db                 *gorm.DB
+}
+
+// NewApprovalService function create a new instance for service
+func NewApprovalService(wr repositories.Repository[domain.Wallet], apr repositories.Repository[domain.ApprovalRequest], ar repositories.Repository[domain.AuditLog], ws TxWalletService, hs TxHoldService, l *log.Logger, db *gorm.DB) ports.IApprovalService {
+	return &approvalService{
+		WalletRepository:   wr,
+		ApprovalRepository: apr,
+		AuditRepository:    ar,
+		WalletService:      ws,
+		HoldService:        hs,
+		logger:             l,
+		db:                 db,
+	}
+}
+
+func (a *approvalService) GetApproval(id string) (*domain.ApprovalRequest, error) {
+	approval, err := a.ApprovalRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+func (a *approvalService) GetApprovals(filter common.ApprovalFilterRequest, pagination *utils.Pagination) (*utils.Pagination, error) {
+	status := domain.APPROVAL_PENDING
+	if filter.Status != "" {
+		status = domain.ApprovalStatus(filter.Status)
+	}
+
+	return a.ApprovalRepository.GetWhere(pagination, func(db *gorm.DB) *gorm.DB {
+		db = db.Where("status = ?", status)
+		if filter.WalletID != "" {
+			db = db.Where("wallet_id = ?", filter.WalletID)
+		}
+		if filter.Maker != "" {
+			db = db.Where("maker = ?", filter.Maker)
+		}
+		return db
+	})
+}
+
+// Approve carries out a held debit on the word of a checker who is not its maker. A hold is placed
+// rather than posted, the transaction is nil then
+func (a *approvalService) Approve(params common.GetByIDRequest, checker *domain.Principal) (*domain.ApprovalRequest, *domain.Transaction, error) {
+	uw := tx.NewGormUnitOfWork(a.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the wallets are left to settlement, which locks every wallet it touches in order
+	approval, err := a.lockApproval(t, params.ID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if approval.Maker == checker.Subject {
+		err = domain.ErrSelfApproval
+		return nil, nil, err
+	}
+
+	if approval.ExpiresAt.Before(time.Now()) {
+		err = domain.ErrApprovalExpired
+		return nil, nil, err
+	}
+
+	var transaction *domain.Transaction
+	if approval.Kind == domain.APPROVAL_HOLD {
+		err = a.placeHold(t, approval)
+	} else {
+		transaction, err = a.WalletService.SettleApproval(t, approval)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	approval.Status = domain.APPROVAL_APPROVED
+	approval.Checker = checker.Subject
+	approval.DecidedAt = &now
+
+	details := map[string]interface{}{
+		"approval_id": approval.ID,
+		"kind":        approval.Kind,
+		"maker":       approval.Maker,
+		"amount":      approval.Amount,
+	}
+	if transaction != nil {
+		approval.TransactionID = &transaction.ID
+		details["transaction_id"] = transaction.ID
+	}
+	if approval.HoldID != nil {
+		details["hold_id"] = *approval.HoldID
+	}
+
+	err = a.ApprovalRepository.WithTx(t).Update(approval)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = audit(a.AuditRepository.WithTx(t), checker, domain.TRANSACTION_APPROVED, approval.WalletID, "", details)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return approval, transaction, nil
+}
+
+// Reject turns a held debit down on the word of a checker who is not its maker, giving its funds back
+func (a *approvalService) Reject(params common.GetByIDRequest, reason string, checker *domain.Principal) (*domain.ApprovalRequest, error) {
+	uw := tx.NewGormUnitOfWork(a.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	approval, wallet, err := a.lockPending(t, params.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if approval.Maker == checker.Subject {
+		err = domain.ErrSelfApproval
+		return nil, err
+	}
+
+	approval.Checker = checker.Subject
+	approval.Reason = reason
+
+	err = a.release(t, approval, wallet, domain.APPROVAL_REJECTED)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = audit(a.AuditRepository.WithTx(t), checker, domain.TRANSACTION_REJECTED, wallet.ID, reason, map[string]interface{}{
+		"approval_id": approval.ID,
+		"maker":       approval.Maker,
+		"amount":      approval.Amount,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return approval, nil
+}
+
+func (a *approvalService) ExpireApprovals() (int, error) {
+	approvals, err := a.ApprovalRepository.GetAllBy("status = ? AND expires_at < ?", domain.APPROVAL_PENDING, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, approval := range approvals {
+		if err := a.expire(approval.ID.String()); err != nil {
+			a.logger.Errorf("expiring approval request %v: %v", approval.ID, err)
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+func (a *approvalService) expire(id string) error {
+	uw := tx.NewGormUnitOfWork(a.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	approval, wallet, err := a.lockPending(t, id)
+
+	if err != nil {
+		return err
+	}
+
+	err = a.release(t, approval, wallet, domain.APPROVAL_EXPIRED)
+
+	if err != nil {
+		return err
+	}
+
+	return uw.Commit()
+}
+
+// release settles a pending request without a debit, giving its funds back to the wallet
+func (a *approvalService) release(t *gorm.DB, approval *domain.ApprovalRequest, wallet *domain.Wallet, status domain.ApprovalStatus) error {
+	(*wallet).HeldBalance -= approval.Amount
+
+	if err := a.WalletRepository.WithTx(t).Update(wallet); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	approval.Status = status
+	approval.DecidedAt = &now
+
+	return a.ApprovalRepository.WithTx(t).Update(approval)
+}
+
+// placeHold places an approved hold, its funds move from the approval's reservation to the hold's
+func (a *approvalService) placeHold(t *gorm.DB, approval *domain.ApprovalRequest) error {
+	wallet, err := a.WalletRepository.WithTx(t).GetByIDForUpdate(approval.WalletID.String())
+	if err != nil {
+		return err
+	}
+
+	(*wallet).HeldBalance -= approval.Amount
+
+	hold, err := a.HoldService.PlaceHold(t, wallet, common.CreateHoldRequest{
+		Amount:    approval.Amount,
+		Currency:  string(approval.Currency),
+		Reference: approval.Reference,
+		ExpiresIn: approval.HoldExpiresIn,
+	})
+	if err != nil {
+		return err
+	}
+
+	approval.HoldID = &hold.ID
+	return nil
+}
+
+// lockApproval locks an approval request and checks it still waits on a decision
+func (a *approvalService) lockApproval(t *gorm.DB, id string) (*domain.ApprovalRequest, error) {
+	approval, err := a.ApprovalRepository.WithTx(t).GetByIDForUpdate(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if approval.Status != domain.APPROVAL_PENDING {
+		return nil, domain.ErrApprovalNotPending
+	}
+	return approval, nil
+}
+
+// lockPending locks an approval request together with its wallet and checks it still waits on a decision
+func (a *approvalService) lockPending(t *gorm.DB, id string) (*domain.ApprovalRequest, *domain.Wallet, error) {
+	approval, err := a.lockApproval(t, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wallet, err := a.WalletRepository.WithTx(t).GetByIDForUpdate(approval.WalletID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return approval, wallet, nil
+}
+
+// needsApproval reports whether a debit of amount must wait on a checker before it takes effect
+func needsApproval(amount int64) bool {
+	return amount > config.Instance.GetApprovalThreshold()
+}
+
+// RequestApproval holds approval's amount against a wallet locked within t and queues it for a
+// checker, instead of carrying it out. Every debit path above the threshold comes through here
+func (w *walletService) RequestApproval(t *gorm.DB, wallet *domain.Wallet, approval *domain.ApprovalRequest) error {
+	if approval.Kind == "" {
+		approval.Kind = domain.APPROVAL_TRANSACTION
+	}
+	if approval.Maker == "" {
+		approval.Maker = "anonymous"
+	}
+
+	approval.WalletID = wallet.ID
+	approval.TransactionType = domain.DEBIT
+	approval.Currency = wallet.Currency
+	approval.Status = domain.APPROVAL_PENDING
+	approval.ExpiresAt = time.Now().Add(config.Instance.GetApprovalTTL())
+
+	if err := w.ApprovalRepository.WithTx(t).Persist(approval); err != nil {
+		return err
+	}
+
+	(*wallet).HeldBalance += approval.Amount
+
+	return w.WalletRepository.WithTx(t).Update(wallet)
+}
+
+// requestApproval holds a debit's funds against a wallet locked within t and queues it for a
+// checker, instead of posting it
+func (w *walletService) requestApproval(t *gorm.DB, wallet *domain.Wallet, body common.CreateTransactionRequest, hash, actor string) (*domain.ApprovalRequest, error) {
+	// checks the debit could post today, without posting it
+	transaction, err := w.ReturnTransaction(wallet, body)
+	if err != nil {
+		return nil, err
+	}
+
+	approval := &domain.ApprovalRequest{
+		Kind:        domain.APPROVAL_TRANSACTION,
+		Purpose:     transaction.Purpose,
+		Amount:      transaction.Amount,
+		Reference:   body.Reference,
+		RequestHash: hash,
+		Maker:       actor,
+	}
+
+	if err := w.RequestApproval(t, wallet, approval); err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// findPendingApproval returns the request still waiting on approval under key against a wallet,
+// or nil when there is none. A request with a different hash is a conflict
+func (w *walletService) findPendingApproval(t *gorm.DB, walletID, key, hash string) (*domain.ApprovalRequest, error) {
+	approval, err := w.ApprovalRepository.WithTx(t).GetBy("wallet_id = ? AND reference = ? AND status = ?", walletID, key, domain.APPROVAL_PENDING)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if approval.RequestHash != hash {
+		return nil, domain.ErrIdempotencyConflict
+	}
+	return approval, nil
+}
+
+// SettleApproval carries out an approved transaction, transfer or closure within t, locking the
+// wallets it touches. The hold on its funds is lifted first, then it goes through the same checks
+// it would have without approval. It returns the debit it posted, if any
+func (w *walletService) SettleApproval(t *gorm.DB, approval *domain.ApprovalRequest) (*domain.Transaction, error) {
+	ids := []uuid.UUID{approval.WalletID}
+	if approval.DestinationWalletID != nil {
+		ids = append(ids, *approval.DestinationWalletID)
+	}
+
+	wallets, err := w.lockWallets(t, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet := wallets[approval.WalletID]
+	(*wallet).HeldBalance -= approval.Amount
+
+	switch approval.Kind {
+	case domain.APPROVAL_TRANSFER:
+		return w.settleTransfer(t, wallet, wallets[*approval.DestinationWalletID], approval)
+	case domain.APPROVAL_CLOSURE:
+		sweep, err := w.close(t, wallet, wallets[*approval.DestinationWalletID], approval.Narration, approval.Maker)
+		if err != nil || sweep == nil {
+			return nil, err
+		}
+		return &sweep.Debit, nil
+	}
+
+	body := common.CreateTransactionRequest{
+		TransactionType: string(approval.TransactionType),
+		Purpose:         string(approval.Purpose),
+		Amount:          approval.Amount,
+		Reference:       approval.Reference,
+	}
+
+	if err := w.CheckLimits(t, wallet, body.TransactionType, body.Amount); err != nil {
+		return nil, err
+	}
+
+	transaction, err := w.PostTransaction(t, wallet, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := w.chargeFee(t, wallet, transaction); err != nil {
+		return nil, err
+	}
+
+	if approval.Reference != "" {
+		if err := w.Remember(t, approval.Reference, approval.RequestHash, transaction); err != nil {
+			return nil, err
+		}
+	}
+	return transaction, nil
+}
+
+// settleTransfer moves an approved transfer's funds, converting them at the quote redeemed when
+// it was requested
+func (w *walletService) settleTransfer(t *gorm.DB, source, destination *domain.Wallet, approval *domain.ApprovalRequest) (*domain.Transaction, error) {
+	var quote *domain.FXQuote
+	credited := approval.Amount
+	if approval.QuoteID != nil {
+		found, err := w.QuoteRepository.WithTx(t).GetByID(approval.QuoteID.String())
+		if err != nil {
+			return nil, err
+		}
+		quote, credited = found, found.DestinationAmount
+	}
+
+	if err := w.CheckLimits(t, source, string(domain.DEBIT), approval.Amount); err != nil {
+		return nil, err
+	}
+
+	if err := w.CheckLimits(t, destination, string(domain.CREDIT), credited); err != nil {
+		return nil, err
+	}
+
+	debit, _, err := w.moveFunds(t, source, destination, approval.Amount, string(approval.Currency), quote)
+	if err != nil {
+		return nil, err
+	}
+
+	if approval.Reference != "" {
+		if err := w.Remember(t, approval.Reference, approval.RequestHash, debit); err != nil {
+			return nil, err
+		}
+	}
+	return debit, nil
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+	tx "wallet_engine/pkg/unit_of_work"
+	"wallet_engine/pkg/utils"
+)
+
+type customerService struct {
+	CustomerRepository repositories.Repository[domain.Customer]
+	WalletRepository   repositories.Repository[domain.Wallet]
+	logger             *log.Logger
+	db                 *gorm.DB
+}
+
+// NewCustomerService function create a new instance for service
+func NewCustomerService(cr repositories.Repository[domain.Customer], wr repositories.Repository[domain.Wallet], l *log.Logger, db *gorm.DB) ports.ICustomerService {
+	return &customerService{
+		CustomerRepository: cr,
+		WalletRepository:   wr,
+		logger:             l,
+		db:                 db,
+	}
+}
+
+func (c *customerService) CreateCustomer(body common.CreateCustomerRequest) (*domain.Customer, error) {
+	customer := &domain.Customer{
+		Name:   body.Name,
+		Email:  strings.ToLower(body.Email),
+		Phone:  body.Phone,
+		Tier:   domain.TIER_ONE,
+		Status: domain.CUSTOMER_ACTIVE,
+	}
+	if body.Tier != "" {
+		customer.Tier = domain.Tier(body.Tier)
+	}
+
+	if err := c.checkEmail(customer.Email); err != nil {
+		return nil, err
+	}
+
+	if err := c.CustomerRepository.Persist(customer); err != nil {
+		c.logger.Error(err)
+		return nil, err
+	}
+	return customer, nil
+}
+
+// checkEmail refuses an email held by any customer, deleted ones included since the unique index
+// still covers them
+func (c *customerService) checkEmail(email string) error {
+	_, err := c.CustomerRepository.Unscoped().GetBy("email = ?", email)
+	if err == nil {
+		return domain.ErrCustomerExists
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (c *customerService) GetCustomers(pagination *utils.Pagination) (*utils.Pagination, error) {
+	customers, err := c.CustomerRepository.Get(pagination)
+	if err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
+func (c *customerService) GetCustomer(params common.GetByIDRequest) (*domain.Customer, error) {
+	customer, err := c.CustomerRepository.GetByID(params.ID)
+	if err != nil {
+		return nil, err
+	}
+	return customer, nil
+}
+
+// UpdateCustomer changes the fields body sets. A new KYC tier moves every wallet the customer
+// holds onto that tier's limits along with them
+func (c *customerService) UpdateCustomer(params common.GetByIDRequest, body common.UpdateCustomerRequest) (*domain.Customer, error) {
+	uw := tx.NewGormUnitOfWork(c.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := c.CustomerRepository.WithTx(t).GetByIDForUpdate(params.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if body.Email != nil && !strings.EqualFold(*body.Email, customer.Email) {
+		err = c.checkEmail(strings.ToLower(*body.Email))
+
+		if err != nil {
+			return nil, err
+		}
+		customer.Email = strings.ToLower(*body.Email)
+	}
+
+	if body.Name != nil {
+		customer.Name = *body.Name
+	}
+	if body.Phone != nil {
+		customer.Phone = *body.Phone
+	}
+	if body.Status != nil {
+		customer.Status = domain.CustomerStatus(*body.Status)
+	}
+
+	if body.Tier != nil && domain.Tier(*body.Tier) != customer.Tier {
+		customer.Tier = domain.Tier(*body.Tier)
+
+		var wallets []domain.Wallet
+		wallets, err = c.WalletRepository.WithTx(t).GetAllBy("owner = ?", customer.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// each wallet is saved whole, so it is reread under its row lock first
+		for _, owned := range wallets {
+			var wallet *domain.Wallet
+			wallet, err = c.WalletRepository.WithTx(t).GetByIDForUpdate(owned.ID.String())
+
+			if err != nil {
+				return nil, err
+			}
+
+			wallet.Tier = customer.Tier
+			err = c.WalletRepository.WithTx(t).Update(wallet)
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = c.CustomerRepository.WithTx(t).Update(customer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// DeleteCustomer removes a customer who no longer holds any wallet
+func (c *customerService) DeleteCustomer(params common.GetByIDRequest) error {
+	customer, err := c.CustomerRepository.GetByID(params.ID)
+	if err != nil {
+		return err
+	}
+
+	wallets, err := c.WalletRepository.Count("owner = ?", customer.ID)
+	if err != nil {
+		return err
+	}
+	if wallets > 0 {
+		return domain.ErrCustomerHasWallets
+	}
+
+	if err := c.CustomerRepository.Delete(params.ID, domain.Customer{}); err != nil {
+		c.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (c *customerService) GetCustomerWallets(params common.GetByIDRequest) ([]domain.Wallet, error) {
+	customer, err := c.CustomerRepository.GetByID(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	wallets, err := c.WalletRepository.GetAllWhere(func(db *gorm.DB) *gorm.DB {
+		return db.Where("owner = ?", customer.ID).Order("created_at asc")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wallets, nil
+}
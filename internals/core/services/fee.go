@@ -0,0 +1,221 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+	tx "wallet_engine/pkg/unit_of_work"
+)
+
+type feeService struct {
+	FeeRepository  repositories.Repository[domain.FeeSchedule]
+	BandRepository repositories.Repository[domain.FeeBand]
+	logger         *log.Logger
+	db             *gorm.DB
+}
+
+// NewFeeService function create a new instance for service
+func NewFeeService(fr repositories.Repository[domain.FeeSchedule], br repositories.Repository[domain.FeeBand], l *log.Logger, db *gorm.DB) ports.IFeeService {
+	return &feeService{
+		FeeRepository:  fr,
+		BandRepository: br,
+		logger:         l,
+		db:             db,
+	}
+}
+
+func (f *feeService) GetFeeSchedules() ([]domain.FeeSchedule, error) {
+	schedules, err := f.FeeRepository.GetAllWhere(withBands, func(db *gorm.DB) *gorm.DB {
+		return db.Order("purpose asc, currency asc, tier asc")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// SaveFeeSchedule creates the schedule of body's purpose, tier and currency, or replaces it
+// bands and all when one exists
+func (f *feeService) SaveFeeSchedule(body common.SaveFeeScheduleRequest) (*domain.FeeSchedule, error) {
+	currency := domain.DefaultCurrency
+	if body.Currency != "" {
+		parsed, err := domain.ParseCurrency(body.Currency)
+		if err != nil {
+			return nil, err
+		}
+		currency = parsed
+	}
+
+	if err := validateFeeSchedule(body); err != nil {
+		return nil, err
+	}
+
+	uw := tx.NewGormUnitOfWork(f.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	schedule, err := f.FeeRepository.WithTx(t).GetBy("purpose = ? AND tier = ? AND currency = ?", body.Purpose, body.Tier, currency)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		schedule, err = &domain.FeeSchedule{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.ID.String() != "00000000-0000-0000-0000-000000000000" {
+		var bands []domain.FeeBand
+		bands, err = f.BandRepository.WithTx(t).GetAllBy("fee_schedule_id = ?", schedule.ID)
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, band := range bands {
+			err = f.BandRepository.WithTx(t).Unscoped().Delete(band.ID.String(), domain.FeeBand{})
+
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	schedule.Purpose = domain.PurposeType(body.Purpose)
+	schedule.Tier = domain.Tier(body.Tier)
+	schedule.Currency = currency
+	schedule.Type = domain.FeeType(body.Type)
+	schedule.FlatAmount = body.FlatAmount
+	schedule.RateBps = body.RateBps
+	schedule.MinFee = body.MinFee
+	schedule.MaxFee = body.MaxFee
+	schedule.Bands = nil
+	for _, band := range body.Bands {
+		schedule.Bands = append(schedule.Bands, domain.FeeBand{
+			UpTo:       band.UpTo,
+			FlatAmount: band.FlatAmount,
+			RateBps:    band.RateBps,
+		})
+	}
+
+	err = f.FeeRepository.WithTx(t).Update(schedule)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
+
+func (f *feeService) DeleteFeeSchedule(id string) error {
+	if _, err := f.FeeRepository.GetByID(id); err != nil {
+		return err
+	}
+
+	bands, err := f.BandRepository.GetAllBy("fee_schedule_id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	for _, band := range bands {
+		if err := f.BandRepository.Unscoped().Delete(band.ID.String(), domain.FeeBand{}); err != nil {
+			return err
+		}
+	}
+
+	// removed for good so the purpose, tier and currency can be priced again
+	return f.FeeRepository.Unscoped().Delete(id, domain.FeeSchedule{})
+}
+
+// validateFeeSchedule checks a tiered schedule has bands with rising ceilings, only the last
+// may be open ended, and that the caps do not cross
+func validateFeeSchedule(body common.SaveFeeScheduleRequest) error {
+	if body.MinFee > 0 && body.MaxFee > 0 && body.MinFee > body.MaxFee {
+		return fmt.Errorf("%w: min fee above max fee", domain.ErrInvalidFeeSchedule)
+	}
+
+	if body.Type != string(domain.TIERED_FEE) {
+		return nil
+	}
+
+	if len(body.Bands) == 0 {
+		return fmt.Errorf("%w: a tiered schedule needs bands", domain.ErrInvalidFeeSchedule)
+	}
+
+	var ceiling int64
+	for i, band := range body.Bands {
+		last := i == len(body.Bands)-1
+		if band.UpTo == 0 && !last {
+			return fmt.Errorf("%w: only the last band may be open ended", domain.ErrInvalidFeeSchedule)
+		}
+		if band.UpTo != 0 && band.UpTo <= ceiling {
+			return fmt.Errorf("%w: band ceilings must rise", domain.ErrInvalidFeeSchedule)
+		}
+		ceiling = band.UpTo
+	}
+	return nil
+}
+
+// withBands loads a schedule's bands lowest ceiling first with the open ended band last
+func withBands(db *gorm.DB) *gorm.DB {
+	return db.Preload("Bands", func(db *gorm.DB) *gorm.DB {
+		return db.Order("up_to = 0, up_to asc")
+	})
+}
+
+// chargeFee debits the fee the wallet's schedule sets on parent's purpose as a transaction of
+// its own, linked to parent and sharing its reference. Nothing is charged without a schedule
+func (w *walletService) chargeFee(t *gorm.DB, wallet *domain.Wallet, parent *domain.Transaction) error {
+	schedules, err := w.FeeRepository.WithTx(t).GetAllWhere(withBands, func(db *gorm.DB) *gorm.DB {
+		return db.Where("purpose = ? AND currency = ? AND tier IN (?, '')", parent.Purpose, wallet.Currency, wallet.Tier).
+			Order("tier desc").Limit(1)
+	})
+	if err != nil || len(schedules) == 0 {
+		return err
+	}
+
+	fee := schedules[0].Compute(parent.Amount)
+	if fee <= 0 {
+		return nil
+	}
+
+	transaction, err := w.ReturnTransaction(wallet, common.CreateTransactionRequest{
+		TransactionType: string(domain.DEBIT),
+		Purpose:         string(domain.FEE),
+		Amount:          fee,
+	})
+	if err != nil {
+		return err
+	}
+
+	transaction.Reference = parent.Reference
+	transaction.ParentID = &parent.ID
+
+	if err := w.post(t, wallet, transaction); err != nil {
+		return err
+	}
+
+	parent.Fees = append(parent.Fees, *transaction)
+	return nil
+}
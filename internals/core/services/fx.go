@@ -0,0 +1,95 @@
+package services
+
+import (
+	"math/big"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+	"wallet_engine/pkg/config"
+)
+
+// rateDecimals is the precision rates are recorded with on quotes and transactions
+const rateDecimals = 10
+
+type fxService struct {
+	QuoteRepository repositories.Repository[domain.FXQuote]
+	Rates           ports.IFXRateProvider
+	logger          *log.Logger
+}
+
+// NewFXService function create a new instance for service
+func NewFXService(qr repositories.Repository[domain.FXQuote], rp ports.IFXRateProvider, l *log.Logger) ports.IFXService {
+	return &fxService{
+		QuoteRepository: qr,
+		Rates:           rp,
+		logger:          l,
+	}
+}
+
+func (f *fxService) CreateQuote(body common.CreateQuoteRequest) (*domain.FXQuote, error) {
+	source, err := domain.ParseCurrency(body.SourceCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := domain.ParseCurrency(body.DestinationCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	mid, err := f.Rates.Rate(source, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	spread := config.Instance.GetFXSpreadBps()
+	rate := new(big.Rat).Mul(mid, big.NewRat(10000-spread, 10000))
+
+	converted := convert(body.SourceAmount, rate, source, destination)
+	if converted <= 0 {
+		return nil, domain.ErrConversionTooSmall
+	}
+
+	quote := &domain.FXQuote{
+		SourceCurrency:      source,
+		DestinationCurrency: destination,
+		MidRate:             mid.FloatString(rateDecimals),
+		Rate:                rate.FloatString(rateDecimals),
+		SpreadBps:           spread,
+		SourceAmount:        body.SourceAmount,
+		DestinationAmount:   converted,
+		ExpiresAt:           time.Now().Add(config.Instance.GetFXQuoteTTL()),
+	}
+
+	err = f.QuoteRepository.Persist(quote)
+	if err != nil {
+		f.logger.Error(err)
+		return nil, err
+	}
+	return quote, nil
+}
+
+// convert applies rate to an amount in the minor units of from, rescaling to the minor units of
+// to and rounding down so a conversion never pays out more than the rate allows
+func convert(amount int64, rate *big.Rat, from, to domain.Currency) int64 {
+	value := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate)
+
+	shift := to.Exponent() - from.Exponent()
+	if shift < 0 {
+		shift = -shift
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shift)), nil))
+
+	if to.Exponent() > from.Exponent() {
+		value.Mul(value, scale)
+	} else {
+		value.Quo(value, scale)
+	}
+
+	return new(big.Int).Quo(value.Num(), value.Denom()).Int64()
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+	"wallet_engine/pkg/config"
+	tx "wallet_engine/pkg/unit_of_work"
+)
+
+type holdService struct {
+	WalletRepository repositories.Repository[domain.Wallet]
+	HoldRepository   repositories.Repository[domain.Hold]
+	WalletService    TxWalletService
+	logger           *log.Logger
+	db               *gorm.DB
+}
+
+// TxHoldService is the hold service as the services placing holds within their own unit of work
+// see it, PlaceHold is kept off ports.IHoldService so the port does not depend on gorm
+type TxHoldService interface {
+	ports.IHoldService
+	PlaceHold(t *gorm.DB, wallet *domain.Wallet, body common.CreateHoldRequest) (*domain.Hold, error)
+}
+
+// NewHoldService function create a new instance for service
+func NewHoldService(wr repositories.Repository[domain.Wallet], hr repositories.Repository[domain.Hold], ws TxWalletService, l *log.Logger, db *gorm.DB) TxHoldService {
+	return &holdService{
+		WalletRepository: wr,
+		HoldRepository:   hr,
+		WalletService:    ws,
+		logger:           l,
+		db:               db,
+	}
+}
+
+func (h *holdService) GetHoldByID(id string) (*domain.Hold, error) {
+	hold, err := h.HoldRepository.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// CreateHold reserves funds on a wallet on behalf of actor. A hold above the approval threshold
+// is not placed, its funds are held and a *domain.PendingApprovalError returned instead
+func (h *holdService) CreateHold(params common.GetByIDRequest, body common.CreateHoldRequest, actor string) (*domain.Hold, error) {
+	uw := tx.NewGormUnitOfWork(h.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	wallet, err := h.WalletRepository.WithTx(t).GetByIDForUpdate(params.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the hold is a debit in waiting, above the threshold it waits on a checker like the debit would
+	if needsApproval(body.Amount) {
+		err = h.checkHold(t, wallet, body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		approval := &domain.ApprovalRequest{
+			Kind:          domain.APPROVAL_HOLD,
+			Purpose:       domain.CAPTURE,
+			Amount:        body.Amount,
+			Reference:     body.Reference,
+			Maker:         actor,
+			HoldExpiresIn: body.ExpiresIn,
+		}
+
+		err = h.WalletService.RequestApproval(t, wallet, approval)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = uw.Commit()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &domain.PendingApprovalError{Approval: approval}
+	}
+
+	hold, err := h.PlaceHold(t, wallet, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// PlaceHold reserves funds on a wallet the caller has already locked within t
+func (h *holdService) PlaceHold(t *gorm.DB, wallet *domain.Wallet, body common.CreateHoldRequest) (*domain.Hold, error) {
+	if err := h.checkHold(t, wallet, body); err != nil {
+		return nil, err
+	}
+
+	ttl := config.Instance.GetHoldTTL()
+	if body.ExpiresIn > 0 {
+		ttl = time.Duration(body.ExpiresIn) * time.Second
+	}
+
+	hold := &domain.Hold{
+		WalletID:  wallet.ID,
+		Amount:    body.Amount,
+		Currency:  wallet.Currency,
+		Status:    domain.HOLD_ACTIVE,
+		Reference: body.Reference,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	if err := h.HoldRepository.WithTx(t).Persist(hold); err != nil {
+		return nil, err
+	}
+
+	(*wallet).HeldBalance += hold.Amount
+
+	if err := h.WalletRepository.WithTx(t).Update(wallet); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+// checkHold checks a wallet locked within t could have body's funds reserved on it today
+func (h *holdService) checkHold(t *gorm.DB, wallet *domain.Wallet, body common.CreateHoldRequest) error {
+	if body.Currency != "" {
+		currency, err := domain.ParseCurrency(body.Currency)
+		if err != nil {
+			return err
+		}
+
+		if currency != wallet.Currency {
+			return domain.ErrCurrencyMismatch
+		}
+	}
+
+	if !wallet.Status.AllowsDebit() {
+		return domain.ErrDebitNotAllowed
+	}
+
+	if wallet.Available() < body.Amount {
+		return domain.ErrInsufficientBalance
+	}
+
+	// the hold is a debit in waiting, it must fit the limits the debit will be held to
+	return h.WalletService.CheckLimits(t, wallet, string(domain.DEBIT), body.Amount)
+}
+
+func (h *holdService) CaptureHold(params common.GetByIDRequest, body common.CaptureHoldRequest) (*domain.Hold, *domain.Transaction, error) {
+	uw := tx.NewGormUnitOfWork(h.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hold, wallet, err := h.lockActive(t, params.ID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if hold.ExpiresAt.Before(time.Now()) {
+		err = domain.ErrHoldExpired
+		return nil, nil, err
+	}
+
+	amount := hold.Amount
+	if body.Amount > 0 {
+		amount = body.Amount
+	}
+
+	if amount > hold.Amount {
+		err = domain.ErrCaptureExceedsHold
+		return nil, nil, err
+	}
+
+	// limits are checked again, debits posted since the hold was placed count against it
+	err = h.WalletService.CheckLimits(t, wallet, string(domain.DEBIT), amount)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the whole reservation is lifted, anything not captured goes back to the available balance
+	(*wallet).HeldBalance -= hold.Amount
+
+	transaction, err := h.WalletService.PostTransaction(t, wallet, common.CreateTransactionRequest{
+		TransactionType: string(domain.DEBIT),
+		Purpose:         string(domain.CAPTURE),
+		Amount:          amount,
+		Reference:       hold.Reference,
+	})
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hold.Status = domain.HOLD_CAPTURED
+	hold.CapturedAmount = amount
+	hold.TransactionID = &transaction.ID
+
+	err = h.HoldRepository.WithTx(t).Update(hold)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return hold, transaction, nil
+}
+
+func (h *holdService) ReleaseHold(params common.GetByIDRequest) (*domain.Hold, error) {
+	return h.settle(params.ID, domain.HOLD_RELEASED)
+}
+
+func (h *holdService) ExpireHolds() (int, error) {
+	holds, err := h.HoldRepository.GetAllBy("status = ? AND expires_at < ?", domain.HOLD_ACTIVE, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, hold := range holds {
+		if _, err := h.settle(hold.ID.String(), domain.HOLD_EXPIRED); err != nil {
+			h.logger.Errorf("expiring hold %v: %v", hold.ID, err)
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// settle closes an active hold without a debit, giving its funds back to the wallet
+func (h *holdService) settle(id string, status domain.HoldStatus) (*domain.Hold, error) {
+	uw := tx.NewGormUnitOfWork(h.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	hold, wallet, err := h.lockActive(t, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	(*wallet).HeldBalance -= hold.Amount
+
+	err = h.WalletRepository.WithTx(t).Update(wallet)
+
+	if err != nil {
+		return nil, err
+	}
+
+	hold.Status = status
+
+	err = h.HoldRepository.WithTx(t).Update(hold)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return hold, nil
+}
+
+// lockActive locks a hold together with its wallet and checks the hold is still reserving funds
+func (h *holdService) lockActive(t *gorm.DB, id string) (*domain.Hold, *domain.Wallet, error) {
+	hold, err := h.HoldRepository.WithTx(t).GetByIDForUpdate(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if hold.Status != domain.HOLD_ACTIVE {
+		return nil, nil, domain.ErrHoldNotActive
+	}
+
+	wallet, err := h.WalletRepository.WithTx(t).GetByIDForUpdate(hold.WalletID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return hold, wallet, nil
+}
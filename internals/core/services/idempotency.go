@@ -0,0 +1,55 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/pkg/config"
+)
+
+// requestHash fingerprints the parts of a request that must match when its idempotency key is replayed
+func requestHash(parts ...interface{}) string {
+	sum := sha256.Sum256([]byte(fmt.Sprint(parts...)))
+	return hex.EncodeToString(sum[:])
+}
+
+// FindReplay returns the transaction previously produced under key against a wallet, or nil when
+// the key is unused or its retention window has passed. A live key with a different hash is a conflict
+func (w *walletService) FindReplay(t *gorm.DB, walletID, key, hash string) (*domain.Transaction, error) {
+	record, err := w.IdempotencyRepository.WithTx(t).GetBy("wallet_id = ? AND key = ?", walletID, key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if record.ExpiresAt.Before(time.Now()) {
+		// removed for good, a soft deleted row would still hold the key's unique index
+		return nil, w.IdempotencyRepository.WithTx(t).Unscoped().Delete(record.ID.String(), domain.IdempotencyKey{})
+	}
+
+	if record.RequestHash != hash {
+		return nil, domain.ErrIdempotencyConflict
+	}
+
+	return w.TransactionRepository.WithTx(t).GetByIDPreload(record.TransactionID.String(), "Fees")
+}
+
+// Remember stores key against transaction and its wallet within t; the unique index on both makes
+// a concurrent request with the same key fail its commit instead of posting twice
+func (w *walletService) Remember(t *gorm.DB, key, hash string, transaction *domain.Transaction) error {
+	return w.IdempotencyRepository.WithTx(t).Persist(&domain.IdempotencyKey{
+		WalletID:      transaction.WalletID,
+		Key:           key,
+		RequestHash:   hash,
+		TransactionID: transaction.ID,
+		ExpiresAt:     time.Now().Add(config.Instance.GetIdempotencyRetention()),
+	})
+}
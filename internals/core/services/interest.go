@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+	tx "wallet_engine/pkg/unit_of_work"
+)
+
+type interestService struct {
+	WalletRepository      repositories.Repository[domain.Wallet]
+	TransactionRepository repositories.Repository[domain.Transaction]
+	AccrualRepository     repositories.Repository[domain.InterestAccrual]
+	WalletService         TxWalletService
+	PayoutCycle           domain.PayoutCycle
+	logger                *log.Logger
+	db                    *gorm.DB
+}
+
+// NewInterestService function create a new instance for service
+func NewInterestService(wr repositories.Repository[domain.Wallet], tr repositories.Repository[domain.Transaction], ar repositories.Repository[domain.InterestAccrual], ws TxWalletService, cycle domain.PayoutCycle, l *log.Logger, db *gorm.DB) ports.IInterestService {
+	if !cycle.Valid() {
+		cycle = domain.MONTHLY_PAYOUT
+	}
+	return &interestService{
+		WalletRepository:      wr,
+		TransactionRepository: tr,
+		AccrualRepository:     ar,
+		WalletService:         ws,
+		PayoutCycle:           cycle,
+		logger:                l,
+		db:                    db,
+	}
+}
+
+// AccrueInterest brings every open savings wallet up to date, accruing interest for each day that
+// ended by asOf and paying out what has accrued whenever a day closes a payout cycle. It returns
+// how many days were accrued in all. A wallet behind by several days, because the job did not
+// run, catches up on each of them in turn
+func (s *interestService) AccrueInterest(asOf time.Time) (int, error) {
+	wallets, err := s.WalletRepository.GetAllWhere(func(db *gorm.DB) *gorm.DB {
+		return db.Where("product_type = ? AND status <> ?", domain.SAVINGS_PRODUCT, domain.CLOSED)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	through := day(asOf).AddDate(0, 0, -1)
+
+	accrued := 0
+	for _, wallet := range wallets {
+		days, err := s.accrueWallet(wallet.ID.String(), through)
+		if err != nil {
+			s.logger.Errorf("accruing interest on wallet %v: %v", wallet.ID, err)
+			continue
+		}
+		accrued += days
+	}
+	return accrued, nil
+}
+
+// accrueWallet accrues interest on the wallet for every day after the last one accrued up to and
+// including through, all in one transaction under the wallet's row lock
+func (s *interestService) accrueWallet(id string, through time.Time) (int, error) {
+	uw := tx.NewGormUnitOfWork(s.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return 0, err
+	}
+
+	wallet, err := s.WalletRepository.WithTx(t).GetByIDForUpdate(id)
+
+	if err != nil {
+		return 0, err
+	}
+
+	next := day(wallet.CreatedAt)
+	if wallet.InterestAccruedOn != nil {
+		next = day(*wallet.InterestAccruedOn).AddDate(0, 0, 1)
+	}
+
+	days := 0
+	for current := next; !current.After(through); current = current.AddDate(0, 0, 1) {
+		err = s.accrueDay(t, wallet, current)
+
+		if err != nil {
+			return 0, err
+		}
+		days++
+	}
+
+	if days == 0 {
+		err = uw.Commit()
+		return 0, err
+	}
+
+	err = s.WalletRepository.WithTx(t).Update(wallet)
+
+	if err != nil {
+		return 0, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return 0, err
+	}
+
+	return days, nil
+}
+
+// accrueDay records the interest wallet earned on its balance at the close of current and, when
+// current closes a payout cycle, credits everything accrued so far as an interest transaction.
+// A wallet that cannot take credits right now keeps its interest accrued until one that can
+func (s *interestService) accrueDay(t *gorm.DB, wallet *domain.Wallet, current time.Time) error {
+	balance, err := s.closingBalance(t, wallet, current)
+	if err != nil {
+		return err
+	}
+
+	accrual := &domain.InterestAccrual{
+		WalletID: wallet.ID,
+		Day:      current,
+		Balance:  balance,
+		RateBps:  wallet.AnnualInterestRateBps,
+		Amount:   domain.DailyInterest(balance, wallet.AnnualInterestRateBps),
+	}
+	wallet.AccruedInterest += accrual.Amount
+	wallet.InterestAccruedOn = &current
+
+	if s.PayoutCycle.Closes(current) && wallet.AccruedInterest > 0 && wallet.Status.AllowsCredit() {
+		transaction, err := s.WalletService.PostTransaction(t, wallet, common.CreateTransactionRequest{
+			TransactionType: string(domain.CREDIT),
+			Purpose:         string(domain.INTEREST),
+			Amount:          wallet.AccruedInterest,
+			Reference:       fmt.Sprintf("interest:%v:%v", wallet.ID, current.Format("20060102")),
+		})
+		if err != nil {
+			return err
+		}
+		accrual.PaidBy = &transaction.ID
+		wallet.AccruedInterest = 0
+	}
+
+	return s.AccrualRepository.WithTx(t).Persist(accrual)
+}
+
+// closingBalance returns the wallet's balance at the end of current, the balance after the last
+// transaction posted before midnight
+func (s *interestService) closingBalance(t *gorm.DB, wallet *domain.Wallet, current time.Time) (int64, error) {
+	last, err := s.TransactionRepository.WithTx(t).GetAllWhere(func(db *gorm.DB) *gorm.DB {
+		return db.Where("wallet_id = ? AND created_at < ?", wallet.ID, current.AddDate(0, 0, 1)).
+			Order("created_at desc").Limit(1)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(last) == 0 {
+		return 0, nil
+	}
+	return last[0].BalanceAfter, nil
+}
+
+func (s *interestService) GetAccruals(params common.GetByIDRequest) ([]domain.InterestAccrual, error) {
+	wallet, err := s.WalletRepository.GetByID(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	accruals, err := s.AccrualRepository.GetAllWhere(func(db *gorm.DB) *gorm.DB {
+		return db.Where("wallet_id = ?", wallet.ID).Order("day desc")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accruals, nil
+}
+
+// day returns the UTC calendar day t falls on, as its midnight
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+)
+
+type ledgerService struct {
+	AccountRepository     repositories.Repository[domain.LedgerAccount]
+	EntryRepository       repositories.Repository[domain.JournalEntry]
+	PostingRepository     repositories.Repository[domain.Posting]
+	TransactionRepository repositories.Repository[domain.Transaction]
+	logger                *log.Logger
+}
+
+// TxLedgerService is the ledger as the services journaling within their own unit of work see it.
+// The methods taking a transaction are kept off ports.ILedgerService so the port does not depend on gorm
+type TxLedgerService interface {
+	ports.ILedgerService
+	RecordTransaction(t *gorm.DB, wallet *domain.Wallet, transaction *domain.Transaction) error
+	Post(t *gorm.DB, entry *domain.JournalEntry) error
+}
+
+// NewLedgerService function create a new instance for service
+func NewLedgerService(ar repositories.Repository[domain.LedgerAccount], er repositories.Repository[domain.JournalEntry], pr repositories.Repository[domain.Posting], tr repositories.Repository[domain.Transaction], l *log.Logger) TxLedgerService {
+	return &ledgerService{
+		AccountRepository:     ar,
+		EntryRepository:       er,
+		PostingRepository:     pr,
+		TransactionRepository: tr,
+		logger:                l,
+	}
+}
+
+// RecordTransaction journals a wallet transaction within t. The wallet's liability account moves
+// with the transaction, credited when the wallet is credited, and the internal account its
+// purpose maps to takes the other side. A reversal balances against the same internal account
+// as the transaction it reverses
+func (l *ledgerService) RecordTransaction(t *gorm.DB, wallet *domain.Wallet, transaction *domain.Transaction) error {
+	walletAccount, err := l.walletAccount(t, wallet)
+	if err != nil {
+		return err
+	}
+
+	purpose := transaction.Purpose
+	if transaction.ReversalOf != nil {
+		original, err := l.TransactionRepository.WithTx(t).GetByID(transaction.ReversalOf.String())
+		if err != nil {
+			return err
+		}
+		purpose = original.Purpose
+	}
+
+	contra, err := l.AccountRepository.WithTx(t).GetBy("code = ? AND currency = ?", domain.ContraAccount(purpose), transaction.Currency)
+	if err != nil {
+		return fmt.Errorf("ledger account %v %v: %w", domain.ContraAccount(purpose), transaction.Currency, err)
+	}
+
+	amount := transaction.Amount
+	if transaction.TransactionType == domain.CREDIT {
+		amount = -amount
+	}
+
+	return l.Post(t, &domain.JournalEntry{
+		Reference:     transaction.Reference,
+		Description:   fmt.Sprintf("%v %v of wallet %v", transaction.Purpose, transaction.TransactionType, wallet.AccountID),
+		TransactionID: &transaction.ID,
+		Postings: []domain.Posting{
+			{LedgerAccountID: walletAccount.ID, Amount: amount, Currency: transaction.Currency},
+			{LedgerAccountID: contra.ID, Amount: -amount, Currency: transaction.Currency},
+		},
+	})
+}
+
+// Post persists entry and its postings within t, refusing it unless they sum to zero per currency
+func (l *ledgerService) Post(t *gorm.DB, entry *domain.JournalEntry) error {
+	if !entry.Balanced() {
+		return domain.ErrUnbalancedEntry
+	}
+	return l.EntryRepository.WithTx(t).Persist(entry)
+}
+
+// walletAccount returns the liability account of wallet, opening it on the wallet's first
+// posting. The caller holds the wallet's row lock, so two postings cannot both open it
+func (l *ledgerService) walletAccount(t *gorm.DB, wallet *domain.Wallet) (*domain.LedgerAccount, error) {
+	account, err := l.AccountRepository.WithTx(t).GetBy("wallet_id = ?", wallet.ID)
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return account, err
+	}
+
+	account = &domain.LedgerAccount{
+		Code:     fmt.Sprintf("%v-%d", domain.WALLET_ACCOUNT, wallet.AccountID),
+		Name:     fmt.Sprintf("Wallet %d", wallet.AccountID),
+		Type:     domain.LIABILITY,
+		Currency: wallet.Currency,
+		WalletID: &wallet.ID,
+	}
+	if err := l.AccountRepository.WithTx(t).Persist(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func (l *ledgerService) GetAccounts(filter common.LedgerAccountFilterRequest) ([]domain.LedgerAccount, error) {
+	accounts, err := l.AccountRepository.GetAllWhere(func(db *gorm.DB) *gorm.DB {
+		if filter.Type != "" {
+			db = db.Where("type = ?", filter.Type)
+		}
+		if filter.Currency != "" {
+			db = db.Where("currency = ?", strings.ToUpper(filter.Currency))
+		}
+		return db.Order("code asc, currency asc")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// GetTrialBalance lists every account with a balance on its debit or credit side and checks
+// that debits equal credits in each currency
+func (l *ledgerService) GetTrialBalance() (*common.TrialBalance, error) {
+	var sums []struct {
+		LedgerAccountID uuid.UUID
+		Total           int64
+	}
+	if err := l.PostingRepository.SumBy("amount", "ledger_account_id", &sums); err != nil {
+		return nil, err
+	}
+
+	accounts, err := l.AccountRepository.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]domain.LedgerAccount, len(accounts))
+	for _, account := range accounts {
+		byID[account.ID] = account
+	}
+
+	trialBalance := &common.TrialBalance{
+		Lines:    []common.TrialBalanceLine{},
+		Totals:   []common.TrialBalanceTotal{},
+		Balanced: true,
+	}
+	totals := map[domain.Currency]*common.TrialBalanceTotal{}
+
+	for _, sum := range sums {
+		if sum.Total == 0 {
+			continue
+		}
+
+		account := byID[sum.LedgerAccountID]
+		line := common.TrialBalanceLine{
+			Code:     account.Code,
+			Name:     account.Name,
+			Type:     account.Type,
+			Currency: account.Currency,
+		}
+
+		total, ok := totals[account.Currency]
+		if !ok {
+			total = &common.TrialBalanceTotal{Currency: account.Currency}
+			totals[account.Currency] = total
+		}
+
+		if sum.Total > 0 {
+			line.Debit = sum.Total
+			total.Debit += sum.Total
+		} else {
+			line.Credit = -sum.Total
+			total.Credit -= sum.Total
+		}
+		trialBalance.Lines = append(trialBalance.Lines, line)
+	}
+
+	sort.Slice(trialBalance.Lines, func(i, j int) bool {
+		if trialBalance.Lines[i].Currency != trialBalance.Lines[j].Currency {
+			return trialBalance.Lines[i].Currency < trialBalance.Lines[j].Currency
+		}
+		return trialBalance.Lines[i].Code < trialBalance.Lines[j].Code
+	})
+
+	for _, total := range totals {
+		total.Balanced = total.Debit == total.Credit
+		if !total.Balanced {
+			l.logger.Warnf("trial balance out by %d %v", total.Debit-total.Credit, total.Currency)
+			trialBalance.Balanced = false
+		}
+		trialBalance.Totals = append(trialBalance.Totals, *total)
+	}
+
+	sort.Slice(trialBalance.Totals, func(i, j int) bool {
+		return trialBalance.Totals[i].Currency < trialBalance.Totals[j].Currency
+	})
+
+	return trialBalance, nil
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+)
+
+type limitService struct {
+	LimitRepository repositories.Repository[domain.TierLimit]
+	logger          *log.Logger
+}
+
+// NewLimitService function create a new instance for service
+func NewLimitService(lr repositories.Repository[domain.TierLimit], l *log.Logger) ports.ILimitService {
+	return &limitService{
+		LimitRepository: lr,
+		logger:          l,
+	}
+}
+
+func (l *limitService) GetLimits() ([]domain.TierLimit, error) {
+	limits, err := l.LimitRepository.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+func (l *limitService) UpdateLimit(params common.GetByTierRequest, body common.UpdateTierLimitRequest) (*domain.TierLimit, error) {
+	limit, err := l.LimitRepository.GetBy("tier = ?", params.Tier)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		limit = &domain.TierLimit{Tier: domain.Tier(params.Tier)}
+	} else if err != nil {
+		l.logger.Error(err)
+		return nil, err
+	}
+
+	if body.MaxSingleTransaction != nil {
+		limit.MaxSingleTransaction = *body.MaxSingleTransaction
+	}
+	if body.DailyDebitLimit != nil {
+		limit.DailyDebitLimit = *body.DailyDebitLimit
+	}
+	if body.MonthlyDebitLimit != nil {
+		limit.MonthlyDebitLimit = *body.MonthlyDebitLimit
+	}
+	if body.MaxTransactions != nil {
+		limit.MaxTransactions = *body.MaxTransactions
+	}
+	if body.WindowSeconds != nil {
+		limit.WindowSeconds = *body.WindowSeconds
+	}
+
+	err = l.LimitRepository.Update(limit)
+	if err != nil {
+		l.logger.Error(err)
+		return nil, err
+	}
+	return limit, nil
+}
+
+// CheckLimits enforces the wallet tier's limits on a new transaction, or on funds about to be
+// reserved for one. It must run under the wallet's row lock so concurrent requests cannot each
+// see room under the same limit
+func (w *walletService) CheckLimits(t *gorm.DB, wallet *domain.Wallet, transactionType string, amount int64) error {
+	limit, err := w.LimitRepository.WithTx(t).GetBy("tier = ?", wallet.Tier)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if limit.MaxSingleTransaction > 0 && amount > limit.MaxSingleTransaction {
+		return fmt.Errorf("%w: max single transaction of %d", domain.ErrLimitExceeded, limit.MaxSingleTransaction)
+	}
+
+	if limit.MaxTransactions > 0 && limit.WindowSeconds > 0 {
+		window := time.Duration(limit.WindowSeconds) * time.Second
+		count, err := w.TransactionRepository.WithTx(t).Count("wallet_id = ? AND created_at >= ?", wallet.ID, time.Now().Add(-window))
+		if err != nil {
+			return err
+		}
+		if count >= limit.MaxTransactions {
+			return fmt.Errorf("%w: max %d transactions per %v", domain.ErrLimitExceeded, limit.MaxTransactions, window)
+		}
+	}
+
+	if transactionType != string(domain.DEBIT) {
+		return nil
+	}
+
+	now := time.Now()
+	rules := []struct {
+		name  string
+		limit int64
+		since time.Time
+	}{
+		{"daily debit total", limit.DailyDebitLimit, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())},
+		{"monthly debit total", limit.MonthlyDebitLimit, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())},
+	}
+
+	for _, rule := range rules {
+		if rule.limit == 0 {
+			continue
+		}
+		spent, err := w.TransactionRepository.WithTx(t).Sum("amount", "wallet_id = ? AND transaction_type = ? AND created_at >= ?", wallet.ID, domain.DEBIT, rule.since)
+		if err != nil {
+			return err
+		}
+		if spent+amount > rule.limit {
+			return fmt.Errorf("%w: %v of %d", domain.ErrLimitExceeded, rule.name, rule.limit)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+	"wallet_engine/pkg/config"
+)
+
+// reconciliationBatch is how many wallets are loaded at a time while reconciling
+const reconciliationBatch = 100
+
+type reconciliationService struct {
+	WalletRepository      repositories.Repository[domain.Wallet]
+	TransactionRepository repositories.Repository[domain.Transaction]
+	logger                *log.Logger
+}
+
+// NewReconciliationService function create a new instance for service
+func NewReconciliationService(wr repositories.Repository[domain.Wallet], tr repositories.Repository[domain.Transaction], l *log.Logger) ports.IReconciliationService {
+	return &reconciliationService{
+		WalletRepository:      wr,
+		TransactionRepository: tr,
+		logger:                l,
+	}
+}
+
+// Reconcile scans every wallet, deleted ones included, checking its transaction history chains
+// from zero up to its balance, that the balance equals its credits less its debits and that no
+// transaction was edited after it was sealed
+func (r *reconciliationService) Reconcile() (*common.ReconciliationReport, error) {
+	report := &common.ReconciliationReport{
+		StartedAt:     time.Now(),
+		Discrepancies: []common.Discrepancy{},
+	}
+
+	err := r.WalletRepository.Unscoped().FindInBatches(reconciliationBatch, func(wallets []domain.Wallet) error {
+		for i := range wallets {
+			if err := r.reconcileWallet(&wallets[i], report); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+func (r *reconciliationService) VerifyChain(params common.GetByIDRequest) (*common.ChainVerification, error) {
+	wallet, err := r.WalletRepository.Unscoped().GetByID(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.history(wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	verification := verifyChain(wallet, entries)
+	if !verification.Intact {
+		r.logger.Warnf("wallet %v hash chain broken at %v: %v", wallet.ID, verification.FirstTampered, verification.Reason)
+	}
+	return verification, nil
+}
+
+// verifyChain walks entries oldest first, recomputing each hash and checking it points at the
+// one before, and stops at the first that does not. Entries posted before hashing existed carry
+// no seal and are skipped, but an unsealed entry after a sealed one has had its seal stripped.
+// The last hash must be the wallet's chain head, or entries were cut off the end
+func verifyChain(wallet *domain.Wallet, entries []domain.Transaction) *common.ChainVerification {
+	key := []byte(config.Instance.GetLedgerHashKey())
+
+	verification := &common.ChainVerification{
+		WalletID: wallet.ID,
+		Entries:  int64(len(entries)),
+		Intact:   true,
+	}
+
+	previous := ""
+	for i := range entries {
+		entry := &entries[i]
+
+		reason := ""
+		switch {
+		case entry.Hash == "" && verification.Verified == 0:
+			verification.Unsealed++
+			continue
+		case entry.Hash == "":
+			reason = "seal removed"
+		case entry.PreviousHash != previous:
+			reason = "previous hash does not match the preceding transaction"
+		case entry.ComputeHash(key) != entry.Hash:
+			reason = "hash does not match the transaction's contents"
+		}
+
+		if reason != "" {
+			verification.Intact = false
+			verification.FirstTampered = &entry.ID
+			verification.Reason = reason
+			return verification
+		}
+
+		verification.Verified++
+		previous = entry.Hash
+	}
+
+	if previous != wallet.ChainHead {
+		verification.Intact = false
+		verification.Reason = "latest transaction is not the wallet's chain head"
+	}
+	return verification
+}
+
+// history returns every transaction of wallet, oldest first
+func (r *reconciliationService) history(wallet *domain.Wallet) ([]domain.Transaction, error) {
+	return r.TransactionRepository.GetAllWhere(func(db *gorm.DB) *gorm.DB {
+		return db.Where("wallet_id = ?", wallet.ID).Order("created_at asc")
+	})
+}
+
+func (r *reconciliationService) reconcileWallet(wallet *domain.Wallet, report *common.ReconciliationReport) error {
+	entries, err := r.history(wallet)
+	if err != nil {
+		return err
+	}
+
+	found := func(entry *domain.Transaction, kind domain.DiscrepancyKind, expected, actual int64) {
+		discrepancy := common.Discrepancy{
+			WalletID:  wallet.ID,
+			AccountID: wallet.AccountID,
+			Kind:      kind,
+			Expected:  expected,
+			Actual:    actual,
+		}
+		if entry != nil {
+			discrepancy.TransactionID = &entry.ID
+		}
+		report.Discrepancies = append(report.Discrepancies, discrepancy)
+	}
+
+	var previous, net int64
+	for i := range entries {
+		entry := &entries[i]
+
+		if entry.BalanceBefore != previous {
+			if i == 0 {
+				found(entry, domain.GAP, previous, entry.BalanceBefore)
+			} else {
+				found(entry, domain.BROKEN_CHAIN, previous, entry.BalanceBefore)
+			}
+		}
+
+		movement := entry.Amount
+		if entry.TransactionType == domain.DEBIT {
+			movement = -movement
+		}
+		if entry.BalanceAfter != entry.BalanceBefore+movement {
+			found(entry, domain.ENTRY_MISMATCH, entry.BalanceBefore+movement, entry.BalanceAfter)
+		}
+
+		net += movement
+		previous = entry.BalanceAfter
+	}
+
+	if wallet.Balance != previous {
+		found(nil, domain.BALANCE_MISMATCH, previous, wallet.Balance)
+	}
+
+	if wallet.Balance != net {
+		found(nil, domain.JOURNAL_MISMATCH, net, wallet.Balance)
+	}
+
+	if verification := verifyChain(wallet, entries); !verification.Intact {
+		report.Discrepancies = append(report.Discrepancies, common.Discrepancy{
+			WalletID:      wallet.ID,
+			AccountID:     wallet.AccountID,
+			TransactionID: verification.FirstTampered,
+			Kind:          domain.TAMPERED,
+		})
+	}
+
+	report.WalletsScanned++
+	report.TransactionsScanned += int64(len(entries))
+	return nil
+}
+
+// WriteReport saves report into dir as both JSON and CSV, named after the time the run started,
+// and returns the paths written
+func (r *reconciliationService) WriteReport(report *common.ReconciliationReport, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	name := filepath.Join(dir, fmt.Sprintf("reconciliation-%v", report.StartedAt.UTC().Format("20060102T150405Z")))
+
+	payload, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(name+".json", payload, 0o644); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Create(name + ".csv")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	rows := [][]string{{"wallet_id", "account_id", "transaction_id", "kind", "expected", "actual"}}
+	for _, discrepancy := range report.Discrepancies {
+		transaction := ""
+		if discrepancy.TransactionID != nil {
+			transaction = discrepancy.TransactionID.String()
+		}
+		rows = append(rows, []string{
+			discrepancy.WalletID.String(),
+			strconv.FormatInt(discrepancy.AccountID, 10),
+			transaction,
+			string(discrepancy.Kind),
+			strconv.FormatInt(discrepancy.Expected, 10),
+			strconv.FormatInt(discrepancy.Actual, 10),
+		})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+
+	return []string{name + ".json", name + ".csv"}, nil
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/repositories"
+	"wallet_engine/pkg/cron"
+)
+
+// scheduleBatch caps how many due schedules one sweep runs, the rest wait for the next sweep
+const scheduleBatch = 100
+
+type scheduleService struct {
+	ScheduleRepository  repositories.Repository[domain.ScheduledTransaction]
+	ExecutionRepository repositories.Repository[domain.ScheduledExecution]
+	WalletService       ports.IWalletService
+	logger              *log.Logger
+}
+
+// NewScheduleService function create a new instance for service
+func NewScheduleService(sr repositories.Repository[domain.ScheduledTransaction], er repositories.Repository[domain.ScheduledExecution], ws ports.IWalletService, l *log.Logger) ports.IScheduleService {
+	return &scheduleService{
+		ScheduleRepository:  sr,
+		ExecutionRepository: er,
+		WalletService:       ws,
+		logger:              l,
+	}
+}
+
+func (s *scheduleService) CreateSchedule(body common.CreateScheduleRequest) (*domain.ScheduledTransaction, error) {
+	if (body.Cron == "") == (body.IntervalSeconds == 0) {
+		return nil, fmt.Errorf("%w: give either cron or interval_seconds", domain.ErrInvalidSchedule)
+	}
+
+	wallet, err := s.WalletService.GetWalletByID(body.WalletID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := &domain.ScheduledTransaction{
+		WalletID:        wallet.ID,
+		TransactionType: domain.TxnType(body.TransactionType),
+		Purpose:         domain.PurposeType(body.Purpose),
+		Amount:          body.Amount,
+		Cron:            body.Cron,
+		IntervalSeconds: body.IntervalSeconds,
+		Status:          domain.SCHEDULE_ACTIVE,
+		EndsAt:          body.EndsAt,
+	}
+
+	if body.Currency != "" {
+		if schedule.Currency, err = domain.ParseCurrency(body.Currency); err != nil {
+			return nil, err
+		}
+	}
+
+	if body.DestinationWalletID != "" {
+		if body.DestinationWalletID == body.WalletID {
+			return nil, domain.ErrSameWallet
+		}
+		destination, err := s.WalletService.GetWalletByID(body.DestinationWalletID)
+		if err != nil {
+			return nil, err
+		}
+		schedule.DestinationWalletID = &destination.ID
+		schedule.TransactionType = domain.DEBIT
+		schedule.Purpose = domain.TRANSFER
+	} else if body.TransactionType == "" || body.Purpose == "" {
+		return nil, fmt.Errorf("%w: transaction_type and purpose are required without a destination wallet", domain.ErrInvalidSchedule)
+	}
+
+	start := time.Now()
+	if body.StartAt != nil {
+		start = *body.StartAt
+	}
+
+	// step back a minute so a cron schedule may fire at the start itself
+	schedule.NextRunAt = start
+	if schedule.Cron != "" {
+		if schedule.NextRunAt, err = nextRun(schedule, start.Add(-time.Minute)); err != nil {
+			return nil, err
+		}
+	}
+
+	if schedule.EndsAt != nil && schedule.EndsAt.Before(schedule.NextRunAt) {
+		return nil, fmt.Errorf("%w: it ends before its first run", domain.ErrInvalidSchedule)
+	}
+
+	if err := s.ScheduleRepository.Persist(schedule); err != nil {
+		s.logger.Error(err)
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *scheduleService) GetSchedule(params common.GetByIDRequest) (*domain.ScheduledTransaction, error) {
+	schedule, err := s.ScheduleRepository.GetByIDPreload(params.ID, "Executions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("occurrence desc")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *scheduleService) GetWalletSchedules(params common.GetByIDRequest) ([]domain.ScheduledTransaction, error) {
+	wallet, err := s.WalletService.GetWalletByID(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.ScheduleRepository.GetAllWhere(func(db *gorm.DB) *gorm.DB {
+		return db.Where("wallet_id = ?", wallet.ID).Order("created_at desc")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (s *scheduleService) CancelSchedule(params common.GetByIDRequest) (*domain.ScheduledTransaction, error) {
+	schedule, err := s.ScheduleRepository.GetByID(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if schedule.Status != domain.SCHEDULE_ACTIVE {
+		return nil, domain.ErrScheduleNotActive
+	}
+
+	schedule.Status = domain.SCHEDULE_CANCELLED
+	if err := s.ScheduleRepository.Update(schedule); err != nil {
+		s.logger.Error(err)
+		return nil, err
+	}
+	return schedule, nil
+}
+
+// RunDueSchedules runs every active schedule whose next run has come, oldest first, and returns
+// how many ran. A failed run is recorded and the schedule moves on to its next occurrence
+func (s *scheduleService) RunDueSchedules() (int, error) {
+	now := time.Now()
+
+	due, err := s.ScheduleRepository.GetAllWhere(func(db *gorm.DB) *gorm.DB {
+		return db.Where("status = ? AND next_run_at <= ?", domain.SCHEDULE_ACTIVE, now).
+			Order("next_run_at asc").Limit(scheduleBatch)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range due {
+		if err := s.run(&due[i], now); err != nil {
+			return i, err
+		}
+	}
+	return len(due), nil
+}
+
+// run executes schedule's pending occurrence under a reference unique to it, so a run repeated
+// after a crash, or by a second server, replays the first run's transaction instead of posting
+// again. Occurrences missed while the engine was down are skipped rather than run in a burst
+func (s *scheduleService) run(schedule *domain.ScheduledTransaction, now time.Time) error {
+	occurrence := schedule.NextRunAt
+	reference := fmt.Sprintf("schedule:%v:%d", schedule.ID, occurrence.Unix())
+
+	execution := &domain.ScheduledExecution{
+		ScheduleID: schedule.ID,
+		Occurrence: occurrence,
+		Reference:  reference,
+		Status:     domain.EXECUTION_SUCCEEDED,
+	}
+
+	var pending *domain.PendingApprovalError
+
+	transaction, err := s.execute(schedule, reference)
+	if errors.As(err, &pending) {
+		execution.Status = domain.EXECUTION_PENDING_APPROVAL
+		execution.ApprovalID = &pending.Approval.ID
+	} else if err != nil {
+		s.logger.Warnf("schedule %v occurrence %v failed: %v", schedule.ID, occurrence, err)
+		execution.Status = domain.EXECUTION_FAILED
+		execution.Error = err.Error()
+	} else {
+		execution.TransactionID = &transaction.ID
+	}
+
+	if err := s.ExecutionRepository.Persist(execution); err != nil {
+		// the unique reference means this occurrence was already recorded by an earlier run
+		s.logger.Warnf("schedule %v occurrence %v not recorded: %v", schedule.ID, occurrence, err)
+	}
+
+	next, err := nextRun(schedule, occurrence)
+	for err == nil && !next.After(now) {
+		next, err = nextRun(schedule, next)
+	}
+
+	schedule.LastRunAt = &now
+	if err != nil || (schedule.EndsAt != nil && next.After(*schedule.EndsAt)) {
+		schedule.Status = domain.SCHEDULE_COMPLETED
+	} else {
+		schedule.NextRunAt = next
+	}
+
+	return s.ScheduleRepository.Update(schedule)
+}
+
+func (s *scheduleService) execute(schedule *domain.ScheduledTransaction, reference string) (*domain.Transaction, error) {
+	if schedule.DestinationWalletID != nil {
+		debit, _, err := s.WalletService.Transfer(common.CreateTransferRequest{
+			SourceWalletID:      schedule.WalletID.String(),
+			DestinationWalletID: schedule.DestinationWalletID.String(),
+			Amount:              schedule.Amount,
+			Currency:            string(schedule.Currency),
+			Reference:           reference,
+		}, fmt.Sprintf("schedule:%v", schedule.ID))
+		return debit, err
+	}
+
+	return s.WalletService.CreateTransaction(common.GetByIDRequest{ID: schedule.WalletID.String()}, common.CreateTransactionRequest{
+		TransactionType: string(schedule.TransactionType),
+		Purpose:         string(schedule.Purpose),
+		Amount:          schedule.Amount,
+		Currency:        string(schedule.Currency),
+		AccountID:       schedule.WalletID.String(),
+		Reference:       reference,
+	}, fmt.Sprintf("schedule:%v", schedule.ID))
+}
+
+// nextRun returns the occurrence of schedule that follows after
+func nextRun(schedule *domain.ScheduledTransaction, after time.Time) (time.Time, error) {
+	if schedule.Cron == "" {
+		return after.Add(time.Duration(schedule.IntervalSeconds) * time.Second), nil
+	}
+
+	spec, err := cron.Parse(schedule.Cron)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %v", domain.ErrInvalidSchedule, err)
+	}
+
+	next := spec.Next(after)
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("%w: %q never fires", domain.ErrInvalidSchedule, schedule.Cron)
+	}
+	return next, nil
+}
@@ -2,10 +2,18 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"wallet_engine/internals/repositories"
+	"wallet_engine/pkg/config"
 	tx "wallet_engine/pkg/unit_of_work"
+	"wallet_engine/pkg/utils"
 
 	"wallet_engine/internals/common"
 	"wallet_engine/internals/core/domain"
@@ -15,15 +23,50 @@ import (
 type walletService struct {
 	WalletRepository      repositories.Repository[domain.Wallet]
 	TransactionRepository repositories.Repository[domain.Transaction]
+	IdempotencyRepository repositories.Repository[domain.IdempotencyKey]
+	QuoteRepository       repositories.Repository[domain.FXQuote]
+	LimitRepository       repositories.Repository[domain.TierLimit]
+	StatusRepository      repositories.Repository[domain.WalletStatusChange]
+	FeeRepository         repositories.Repository[domain.FeeSchedule]
+	CustomerRepository    repositories.Repository[domain.Customer]
+	ApprovalRepository    repositories.Repository[domain.ApprovalRequest]
+	Ledger                TxLedgerService
+	AccountNumbers        ports.IAccountNumberGenerator
 	logger                *log.Logger
 	db                    *gorm.DB
 }
 
+// TxWalletService is the wallet service as the services posting within their own unit of work see
+// it. The methods taking a transaction are kept off ports.IWalletService so the port does not
+// depend on gorm
+type TxWalletService interface {
+	ports.IWalletService
+	FindReplay(t *gorm.DB, walletID, key, hash string) (*domain.Transaction, error)
+	Remember(t *gorm.DB, key, hash string, transaction *domain.Transaction) error
+	CheckLimits(t *gorm.DB, wallet *domain.Wallet, transactionType string, amount int64) error
+	PostTransaction(t *gorm.DB, wallet *domain.Wallet, body common.CreateTransactionRequest) (*domain.Transaction, error)
+	ChangeStatus(t *gorm.DB, wallet *domain.Wallet, next domain.State, reason, actor string) error
+	RequestApproval(t *gorm.DB, wallet *domain.Wallet, approval *domain.ApprovalRequest) error
+	SettleApproval(t *gorm.DB, approval *domain.ApprovalRequest) (*domain.Transaction, error)
+}
+
+// accountNumberAttempts caps how many account numbers are drawn before giving up on a wallet
+const accountNumberAttempts = 5
+
 // NewWalletService function create a new instance for service
-func NewWalletService(wr repositories.Repository[domain.Wallet], tr repositories.Repository[domain.Transaction], l *log.Logger, db *gorm.DB) ports.IWalletService {
+func NewWalletService(wr repositories.Repository[domain.Wallet], tr repositories.Repository[domain.Transaction], ir repositories.Repository[domain.IdempotencyKey], qr repositories.Repository[domain.FXQuote], lr repositories.Repository[domain.TierLimit], sr repositories.Repository[domain.WalletStatusChange], fr repositories.Repository[domain.FeeSchedule], cr repositories.Repository[domain.Customer], apr repositories.Repository[domain.ApprovalRequest], ls TxLedgerService, an ports.IAccountNumberGenerator, l *log.Logger, db *gorm.DB) TxWalletService {
 	return &walletService{
 		WalletRepository:      wr,
 		TransactionRepository: tr,
+		IdempotencyRepository: ir,
+		QuoteRepository:       qr,
+		LimitRepository:       lr,
+		StatusRepository:      sr,
+		FeeRepository:         fr,
+		CustomerRepository:    cr,
+		ApprovalRepository:    apr,
+		Ledger:                ls,
+		AccountNumbers:        an,
 		logger:                l,
 		db:                    db,
 	}
@@ -37,44 +80,179 @@ func (w *walletService) GetWalletByID(id string) (*domain.Wallet, error) {
 	return wallet, nil
 }
 
+func (w *walletService) GetWalletWithTransactions(id string) (*domain.Wallet, error) {
+	wallet, err := w.WalletRepository.GetByIDPreload(id, "Transactions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at asc")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
+// GetWalletByAccountNumber looks the number up before its check digit is checked, wallets opened
+// before account numbers were checksummed hold numbers that fail it. A number no wallet holds is
+// only reported invalid when its check digit is wrong
+func (w *walletService) GetWalletByAccountNumber(accountNumber int64) (*domain.Wallet, error) {
+	wallet, err := w.WalletRepository.GetBy("account_id = ?", accountNumber)
+	if errors.Is(err, gorm.ErrRecordNotFound) && !w.AccountNumbers.Valid(accountNumber) {
+		return nil, domain.ErrInvalidAccountNumber
+	}
+	if err != nil {
+		return nil, err
+	}
+	return wallet, nil
+}
+
 func (w *walletService) CreateWallet(wallet *domain.Wallet) error {
-	err := w.WalletRepository.Persist(wallet)
+	if wallet.Status != domain.PENDING && wallet.Status != domain.ACTIVE {
+		return fmt.Errorf("%w: a wallet starts out %v or %v", domain.ErrInvalidStatus, domain.PENDING, domain.ACTIVE)
+	}
+
+	if wallet.Currency == "" {
+		wallet.Currency = domain.DefaultCurrency
+	}
+
+	if !wallet.Currency.Valid() {
+		return domain.ErrUnsupportedCurrency
+	}
+
+	if wallet.ProductType == "" {
+		wallet.ProductType = domain.STANDARD_PRODUCT
+	}
+
+	// the rate is the product's, whoever opens the wallet does not choose what it earns
+	switch wallet.ProductType {
+	case domain.SAVINGS_PRODUCT:
+		wallet.AnnualInterestRateBps = config.Instance.GetSavingsInterestRateBps()
+		if wallet.AnnualInterestRateBps <= 0 || wallet.AnnualInterestRateBps > 10000 {
+			return fmt.Errorf("%w: savings wallets are not offered without an annual interest rate between 1 and 10000 basis points", domain.ErrInvalidProduct)
+		}
+	case domain.STANDARD_PRODUCT:
+		wallet.AnnualInterestRateBps = 0
+	default:
+		return fmt.Errorf("%w: %v", domain.ErrInvalidProduct, wallet.ProductType)
+	}
+
+	customer, err := w.CustomerRepository.GetByID(wallet.Owner.String())
 	if err != nil {
-		w.logger.Error(err)
-		return err
+		return fmt.Errorf("customer %v: %w", wallet.Owner, err)
 	}
-	return nil
+
+	if customer.Status != domain.CUSTOMER_ACTIVE {
+		return domain.ErrCustomerNotActive
+	}
+
+	// a wallet is held on its customer's KYC tier
+	wallet.Tier = customer.Tier
+
+	for attempt := 0; attempt < accountNumberAttempts; attempt++ {
+		accountNumber, err := w.AccountNumbers.Generate()
+		if err != nil {
+			w.logger.Error(err)
+			return err
+		}
+		wallet.AccountID = accountNumber
+
+		err = w.WalletRepository.Persist(wallet)
+		if err == nil {
+			return nil
+		}
+
+		// the unique index rejects a number that is already taken, deleted wallets included,
+		// anything else is a real failure
+		if _, lookupErr := w.WalletRepository.Unscoped().GetBy("account_id = ?", accountNumber); lookupErr != nil {
+			w.logger.Error(err)
+			return err
+		}
+		w.logger.Warnf("account number %d already allocated, retrying", accountNumber)
+	}
+	return domain.ErrAccountNumberExhausted
 }
 
+// DeleteWallet soft deletes an empty wallet. The wallet stays locked from the balance check to
+// the delete, so a credit racing the delete cannot land on a wallet that is going away
 func (w *walletService) DeleteWallet(id string) error {
-	err := w.WalletRepository.Delete(id, domain.Wallet{})
+	uw := tx.NewGormUnitOfWork(w.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return err
+	}
+
+	wallet, err := w.WalletRepository.WithTx(t).GetByIDForUpdate(id)
+
 	if err != nil {
 		w.logger.Error(err)
 		return err
 	}
-	return nil
+
+	if wallet.Balance != 0 || wallet.HeldBalance != 0 {
+		err = domain.ErrWalletNotEmpty
+		return err
+	}
+
+	err = w.WalletRepository.WithTx(t).Delete(id, domain.Wallet{})
+
+	if err != nil {
+		w.logger.Error(err)
+		return err
+	}
+
+	return uw.Commit()
 }
 
 func (w *walletService) UpdateWallet(params common.GetByIDRequest, body common.UpdateWalletRequest) (*domain.Wallet, error) {
-	wallet, err := w.WalletRepository.GetByID(params.ID)
+	if body.Status == nil {
+		wallet, err := w.WalletRepository.GetByID(params.ID)
+		if err != nil {
+			w.logger.Error(err)
+			return nil, err
+		}
+		return wallet, nil
+	}
+
+	uw := tx.NewGormUnitOfWork(w.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
 	if err != nil {
-		w.logger.Error(err)
 		return nil, err
 	}
-	if body.Status != nil {
-		(*wallet).Status = domain.State(*body.Status)
+
+	wallet, err := w.WalletRepository.WithTx(t).GetByIDForUpdate(params.ID)
+
+	if err != nil {
+		return nil, err
 	}
 
-	err = w.WalletRepository.Update(wallet)
+	err = w.ChangeStatus(t, wallet, domain.State(*body.Status), body.Reason, body.Actor)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = uw.Commit()
 
 	if err != nil {
-		w.logger.Error(err)
 		return nil, err
 	}
+
 	return wallet, nil
 }
 
-func (w *walletService) CreateTransaction(params common.GetByIDRequest, body common.CreateTransactionRequest) (*domain.Transaction, error) {
+func (w *walletService) CloseWallet(params common.GetByIDRequest, body common.CloseWalletRequest) (*domain.Wallet, *common.TransferResponse, error) {
 	uw := tx.NewGormUnitOfWork(w.db)
 	t, err := uw.Begin()
 
@@ -85,60 +263,904 @@ func (w *walletService) CreateTransaction(params common.GetByIDRequest, body com
 	}()
 
 	if err != nil {
+		return nil, nil, err
+	}
+
+	walletID, destinationID := uuid.FromStringOrNil(params.ID), uuid.FromStringOrNil(body.DestinationWalletID)
+
+	ids := []uuid.UUID{walletID}
+	if body.DestinationWalletID != "" {
+		ids = append(ids, destinationID)
+	}
+
+	wallets, err := w.lockWallets(t, ids...)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	wallet, destination := wallets[walletID], wallets[destinationID]
+
+	if destination != nil && destination.ID == wallet.ID {
+		err = domain.ErrSameWallet
+		return nil, nil, err
+	}
+
+	// a sweep is a debit like any other, above the threshold it waits on a checker
+	if destination != nil && needsApproval(wallet.Balance) {
+		err = w.closable(wallet, destination)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		approval := &domain.ApprovalRequest{
+			Kind:                domain.APPROVAL_CLOSURE,
+			Purpose:             domain.TRANSFER,
+			Amount:              wallet.Balance,
+			Narration:           body.Reason,
+			Maker:               body.Actor,
+			DestinationWalletID: &destination.ID,
+		}
+
+		err = w.RequestApproval(t, wallet, approval)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = uw.Commit()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, &domain.PendingApprovalError{Approval: approval}
+	}
+
+	sweep, err := w.close(t, wallet, destination, body.Reason, body.Actor)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return wallet, sweep, nil
+}
+
+// closable checks a wallet locked within t can be closed, sweeping its balance into destination
+// when it has one
+func (w *walletService) closable(wallet, destination *domain.Wallet) error {
+	if !wallet.Status.CanTransitionTo(domain.CLOSED) {
+		return fmt.Errorf("%w: %v to %v", domain.ErrStatusTransition, wallet.Status, domain.CLOSED)
+	}
+
+	if wallet.HeldBalance != 0 {
+		return fmt.Errorf("%w: %d is still on hold", domain.ErrWalletNotEmpty, wallet.HeldBalance)
+	}
+
+	if wallet.Balance != 0 {
+		if destination == nil {
+			return fmt.Errorf("%w: a destination wallet is required to sweep %d", domain.ErrWalletNotEmpty, wallet.Balance)
+		}
+		if wallet.Currency != destination.Currency {
+			return domain.ErrCurrencyMismatch
+		}
+	}
+	return nil
+}
+
+// close sweeps the balance of a wallet locked within t into destination and closes it
+func (w *walletService) close(t *gorm.DB, wallet, destination *domain.Wallet, reason, actor string) (*common.TransferResponse, error) {
+	if err := w.closable(wallet, destination); err != nil {
+		return nil, err
+	}
+
+	var sweep *common.TransferResponse
+	if wallet.Balance != 0 {
+		var err error
+		if sweep, err = w.sweep(t, wallet, destination); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.ChangeStatus(t, wallet, domain.CLOSED, reason, actor); err != nil {
 		return nil, err
 	}
+	return sweep, nil
+}
+
+// sweep moves the whole balance of source into destination as a transfer. Limits are not
+// applied, a closure must be able to empty the wallet whatever its size
+func (w *walletService) sweep(t *gorm.DB, source, destination *domain.Wallet) (*common.TransferResponse, error) {
+	if source.Currency != destination.Currency {
+		return nil, domain.ErrCurrencyMismatch
+	}
 
-	wallet, err := w.WalletRepository.GetByIDForUpdate(params.ID)
+	reference := uuid.NewV4().String()
+	amount := source.Balance
 
+	debit, err := w.PostTransaction(t, source, common.CreateTransactionRequest{
+		TransactionType: string(domain.DEBIT),
+		Purpose:         string(domain.TRANSFER),
+		Amount:          amount,
+		Reference:       reference,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	transaction, err := w.ReturnTransaction(wallet, body)
+	credit, err := w.PostTransaction(t, destination, common.CreateTransactionRequest{
+		TransactionType: string(domain.CREDIT),
+		Purpose:         string(domain.TRANSFER),
+		Amount:          amount,
+		Reference:       reference,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &common.TransferResponse{
+		Reference: reference,
+		Debit:     *debit,
+		Credit:    *credit,
+	}, nil
+}
+
+// DeriveBalance recomputes a wallet's balance from its journal, credits less debits, and reports
+// how far the stored balance has drifted from it
+func (w *walletService) DeriveBalance(params common.GetByIDRequest) (*common.LedgerBalance, error) {
+	wallet, err := w.WalletRepository.GetByID(params.ID)
+	if err != nil {
+		return nil, err
+	}
 
+	credits, err := w.TransactionRepository.Sum("amount", "wallet_id = ? AND transaction_type = ?", wallet.ID, domain.CREDIT)
 	if err != nil {
 		return nil, err
 	}
 
-	err = w.TransactionRepository.WithTx(t).Persist(transaction)
+	debits, err := w.TransactionRepository.Sum("amount", "wallet_id = ? AND transaction_type = ?", wallet.ID, domain.DEBIT)
+	if err != nil {
+		return nil, err
+	}
 
+	entries, err := w.TransactionRepository.Count("wallet_id = ?", wallet.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	(*wallet).Balance = transaction.BalanceAfter
+	derived := credits - debits
+	if wallet.Balance != derived {
+		w.logger.Warnf("wallet %v balance %d drifted %d from its journal", wallet.ID, wallet.Balance, wallet.Balance-derived)
+	}
 
-	err = w.WalletRepository.Update(wallet)
+	return &common.LedgerBalance{
+		WalletID:       wallet.ID,
+		Currency:       wallet.Currency,
+		Balance:        wallet.Balance,
+		DerivedBalance: derived,
+		Credits:        credits,
+		Debits:         debits,
+		Entries:        entries,
+		Drift:          wallet.Balance - derived,
+		Balanced:       wallet.Balance == derived,
+	}, nil
+}
 
+func (w *walletService) GetStatusChanges(params common.GetByIDRequest) ([]domain.WalletStatusChange, error) {
+	wallet, err := w.WalletRepository.GetByID(params.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	err = uw.Commit()
+	changes, err := w.StatusRepository.GetAllBy("wallet_id = ?", wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// ChangeStatus moves a wallet locked within t to next, if its lifecycle allows it, and records
+// who made the change and why
+func (w *walletService) ChangeStatus(t *gorm.DB, wallet *domain.Wallet, next domain.State, reason, actor string) error {
+	if !next.Valid() {
+		return domain.ErrInvalidStatus
+	}
+
+	if wallet.Status == next {
+		return nil
+	}
+
+	if !wallet.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %v to %v", domain.ErrStatusTransition, wallet.Status, next)
+	}
+
+	if next == domain.CLOSED && (wallet.Balance != 0 || wallet.HeldBalance != 0) {
+		return domain.ErrWalletNotEmpty
+	}
+
+	if actor == "" {
+		actor = "anonymous"
+	}
+
+	change := &domain.WalletStatusChange{
+		WalletID: wallet.ID,
+		From:     wallet.Status,
+		To:       next,
+		Reason:   reason,
+		Actor:    actor,
+	}
+
+	(*wallet).Status = next
+
+	err := w.WalletRepository.WithTx(t).Update(wallet)
+	if err != nil {
+		return err
+	}
+
+	return w.StatusRepository.WithTx(t).Persist(change)
+}
 
+// CreateTransaction posts body against a wallet on behalf of actor. A debit above the approval
+// threshold is not posted, its funds are held and a *domain.PendingApprovalError returned instead
+func (w *walletService) CreateTransaction(params common.GetByIDRequest, body common.CreateTransactionRequest, actor string) (*domain.Transaction, error) {
+	kind, err := domain.ParseTxnType(body.TransactionType)
 	if err != nil {
 		return nil, err
 	}
+	body.TransactionType = string(kind)
 
-	return transaction, nil
+	hash := requestHash(params.ID, body.TransactionType, body.Purpose, body.Amount, body.Currency)
+
+	transaction, err := w.createTransaction(params, body, hash, actor)
+	if err != nil && body.Reference != "" {
+		// a concurrent request holding the same key may have committed first
+		if replay, _ := w.FindReplay(w.db, params.ID, body.Reference, hash); replay != nil {
+			return replay, nil
+		}
+	}
+	return transaction, err
 }
 
-func (w *walletService) ReturnTransaction(wallet *domain.Wallet, transaction common.CreateTransactionRequest) (*domain.Transaction, error) {
-	var total int64
-	if transaction.TransactionType == "credit" {
-		total = wallet.Balance + transaction.Amount
-	} else {
-		if wallet.Balance < transaction.Amount {
-			return nil, errors.New("insufficient balance")
+func (w *walletService) createTransaction(params common.GetByIDRequest, body common.CreateTransactionRequest, hash, actor string) (*domain.Transaction, error) {
+	uw := tx.NewGormUnitOfWork(w.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if body.Reference != "" {
+		var replay *domain.Transaction
+		replay, err = w.FindReplay(t, params.ID, body.Reference, hash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if replay != nil {
+			err = uw.Commit()
+			return replay, err
+		}
+
+		var pending *domain.ApprovalRequest
+		pending, err = w.findPendingApproval(t, params.ID, body.Reference, hash)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if pending != nil {
+			err = uw.Commit()
+			if err != nil {
+				return nil, err
+			}
+			return nil, &domain.PendingApprovalError{Approval: pending}
+		}
+	}
+
+	wallet, err := w.WalletRepository.WithTx(t).GetByIDForUpdate(params.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.CheckLimits(t, wallet, body.TransactionType, body.Amount)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if domain.TxnType(body.TransactionType) == domain.DEBIT && needsApproval(body.Amount) {
+		var approval *domain.ApprovalRequest
+		approval, err = w.requestApproval(t, wallet, body, hash, actor)
+
+		if err != nil {
+			return nil, err
+		}
+
+		err = uw.Commit()
+
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, &domain.PendingApprovalError{Approval: approval}
+	}
+
+	transaction, err := w.PostTransaction(t, wallet, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.chargeFee(t, wallet, transaction)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if body.Reference != "" {
+		err = w.Remember(t, body.Reference, hash, transaction)
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return transaction, nil
+}
+
+// Transfer moves funds between wallets on behalf of actor. A transfer above the approval threshold
+// is not posted, its funds are held and a *domain.PendingApprovalError returned instead
+func (w *walletService) Transfer(body common.CreateTransferRequest, actor string) (*domain.Transaction, *domain.Transaction, error) {
+	hash := requestHash(body.SourceWalletID, body.DestinationWalletID, body.Amount, body.Currency, body.QuoteID)
+
+	debit, credit, err := w.transfer(body, hash, actor)
+	if err != nil && body.Reference != "" {
+		// a concurrent request holding the same key may have committed first
+		if replay, _ := w.FindReplay(w.db, body.SourceWalletID, body.Reference, hash); replay != nil {
+			if credit, err := w.counterpart(w.db, replay); err == nil {
+				return replay, credit, nil
+			}
+		}
+	}
+	return debit, credit, err
+}
+
+func (w *walletService) transfer(body common.CreateTransferRequest, hash, actor string) (*domain.Transaction, *domain.Transaction, error) {
+	uw := tx.NewGormUnitOfWork(w.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if body.Reference != "" {
+		var replay, credit *domain.Transaction
+		replay, err = w.FindReplay(t, body.SourceWalletID, body.Reference, hash)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if replay != nil {
+			credit, err = w.counterpart(t, replay)
+
+			if err != nil {
+				return nil, nil, err
+			}
+
+			err = uw.Commit()
+			return replay, credit, err
+		}
+
+		var pending *domain.ApprovalRequest
+		pending, err = w.findPendingApproval(t, body.SourceWalletID, body.Reference, hash)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if pending != nil {
+			err = uw.Commit()
+			if err != nil {
+				return nil, nil, err
+			}
+			return nil, nil, &domain.PendingApprovalError{Approval: pending}
+		}
+	}
+
+	sourceID, destinationID := uuid.FromStringOrNil(body.SourceWalletID), uuid.FromStringOrNil(body.DestinationWalletID)
+
+	wallets, err := w.lockWallets(t, sourceID, destinationID)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	source, destination := wallets[sourceID], wallets[destinationID]
+
+	// ids spelt differently may still name the same wallet, only the locked rows can tell
+	if source.ID == destination.ID {
+		err = domain.ErrSameWallet
+		return nil, nil, err
+	}
+
+	credited := body.Amount
+	var quote *domain.FXQuote
+	if body.QuoteID != "" {
+		quote, err = w.redeemQuote(t, body, source, destination)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		credited = quote.DestinationAmount
+	} else if source.Currency != destination.Currency {
+		err = domain.ErrQuoteRequired
+		return nil, nil, err
+	}
+
+	err = w.CheckLimits(t, source, string(domain.DEBIT), body.Amount)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = w.CheckLimits(t, destination, string(domain.CREDIT), credited)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if needsApproval(body.Amount) {
+		// checks the debit leg could post today, without posting it
+		_, err = w.ReturnTransaction(source, common.CreateTransactionRequest{
+			TransactionType: string(domain.DEBIT),
+			Purpose:         string(domain.TRANSFER),
+			Amount:          body.Amount,
+			Currency:        body.Currency,
+		})
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		approval := &domain.ApprovalRequest{
+			Kind:                domain.APPROVAL_TRANSFER,
+			Purpose:             domain.TRANSFER,
+			Amount:              body.Amount,
+			Reference:           body.Reference,
+			RequestHash:         hash,
+			Maker:               actor,
+			DestinationWalletID: &destination.ID,
+		}
+		if quote != nil {
+			approval.QuoteID = &quote.ID
+		}
+
+		err = w.RequestApproval(t, source, approval)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = uw.Commit()
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, &domain.PendingApprovalError{Approval: approval}
+	}
+
+	debit, credit, err := w.moveFunds(t, source, destination, body.Amount, body.Currency, quote)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if body.Reference != "" {
+		err = w.Remember(t, body.Reference, hash, debit)
+
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return debit, credit, nil
+}
+
+// moveFunds posts both legs of a transfer between wallets locked within t, converting the credit
+// at quote when there is one, and charges the source its transfer fee
+func (w *walletService) moveFunds(t *gorm.DB, source, destination *domain.Wallet, amount int64, currency string, quote *domain.FXQuote) (*domain.Transaction, *domain.Transaction, error) {
+	credited := amount
+	if quote != nil {
+		credited = quote.DestinationAmount
+	}
+
+	debit, err := w.ReturnTransaction(source, common.CreateTransactionRequest{
+		TransactionType: string(domain.DEBIT),
+		Purpose:         string(domain.TRANSFER),
+		Amount:          amount,
+		Currency:        currency,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the requested currency names the source side, the credit lands in the destination's own
+	credit, err := w.ReturnTransaction(destination, common.CreateTransactionRequest{
+		TransactionType: string(domain.CREDIT),
+		Purpose:         string(domain.TRANSFER),
+		Amount:          credited,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reference := uuid.NewV4().String()
+	for _, leg := range []*domain.Transaction{debit, credit} {
+		leg.Reference = reference
+		if quote != nil {
+			leg.QuoteID = &quote.ID
+			leg.FXRate = quote.Rate
+			leg.FXSpreadBps = quote.SpreadBps
+			leg.SourceAmount = quote.SourceAmount
+			leg.DestinationAmount = quote.DestinationAmount
+		}
+	}
+
+	if err := w.post(t, source, debit); err != nil {
+		return nil, nil, err
+	}
+
+	if err := w.chargeFee(t, source, debit); err != nil {
+		return nil, nil, err
+	}
+
+	if err := w.post(t, destination, credit); err != nil {
+		return nil, nil, err
+	}
+	return debit, credit, nil
+}
+
+func (w *walletService) ReverseTransaction(params common.GetByIDRequest) (*domain.Transaction, error) {
+	uw := tx.NewGormUnitOfWork(w.db)
+	t, err := uw.Begin()
+
+	defer func() {
+		if err != nil {
+			t.Rollback()
+		}
+	}()
+
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := w.TransactionRepository.WithTx(t).GetByID(params.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if original.Purpose == domain.REVERSAL {
+		err = domain.ErrReverseReversal
+		return nil, err
+	}
+
+	_, err = w.TransactionRepository.WithTx(t).GetBy("reversal_of = ?", original.ID)
+
+	if err == nil {
+		err = domain.ErrAlreadyReversed
+		return nil, err
+	}
+
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	wallet, err := w.WalletRepository.WithTx(t).GetByIDForUpdate(original.WalletID.String())
+
+	if err != nil {
+		return nil, err
+	}
+
+	opposite := domain.CREDIT
+	if original.TransactionType == domain.CREDIT {
+		opposite = domain.DEBIT
+	}
+
+	reversal, err := w.ReturnTransaction(wallet, common.CreateTransactionRequest{
+		TransactionType: string(opposite),
+		Purpose:         string(domain.REVERSAL),
+		Amount:          original.Amount,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// linked only through reversal_of, sharing the reference would make it a leg of a transfer
+	reversal.ReversalOf = &original.ID
+
+	err = w.post(t, wallet, reversal)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = uw.Commit()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return reversal, nil
+}
+
+func (w *walletService) GetWallets(filter common.WalletFilterRequest, pagination *utils.Pagination) (*utils.Pagination, error) {
+	wallets := &w.WalletRepository
+	if filter.IncludeDeleted {
+		wallets = wallets.Unscoped()
+	}
+
+	return wallets.GetWhere(pagination, func(db *gorm.DB) *gorm.DB {
+		if filter.Status != "" {
+			db = db.Where("status = ?", filter.Status)
+		}
+		if filter.Currency != "" {
+			db = db.Where("currency = ?", strings.ToUpper(filter.Currency))
+		}
+		return db
+	})
+}
+
+func (w *walletService) RestoreWallet(id string) (*domain.Wallet, error) {
+	err := w.WalletRepository.Restore(id)
+	if err != nil {
+		w.logger.Error(err)
+		return nil, err
+	}
+	return w.WalletRepository.GetByID(id)
+}
+
+// PurgeWallets removes for good the wallets deleted longer ago than the retention period. A
+// wallet with journal entries or holds is kept soft deleted, its history must outlive it
+func (w *walletService) PurgeWallets() (int, error) {
+	cutoff := time.Now().Add(-config.Instance.GetWalletRetention())
+
+	wallets, err := w.WalletRepository.Unscoped().GetAllBy(
+		"deleted_at IS NOT NULL AND deleted_at < ? "+
+			"AND NOT EXISTS (SELECT 1 FROM transactions WHERE transactions.wallet_id = wallets.id) "+
+			"AND NOT EXISTS (SELECT 1 FROM holds WHERE holds.wallet_id = wallets.id)",
+		cutoff,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, wallet := range wallets {
+		if err := w.WalletRepository.Unscoped().Delete(wallet.ID.String(), domain.Wallet{}); err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (w *walletService) GetTransactions(params common.GetByIDRequest, filter common.TransactionFilterRequest, pagination *utils.Pagination) (*utils.Pagination, error) {
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return nil, domain.ErrInvalidRange
+	}
+
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, domain.ErrInvalidRange
+	}
+
+	wallets := &w.WalletRepository
+	if filter.IncludeDeleted {
+		wallets = wallets.Unscoped()
+	}
+
+	wallet, err := wallets.GetByID(params.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	pagination.Sort = fmt.Sprintf("%v %v", filter.SortBy, filter.Order)
+
+	return w.TransactionRepository.GetWhere(pagination, func(db *gorm.DB) *gorm.DB {
+		db = db.Where("wallet_id = ?", wallet.ID)
+		if filter.TransactionType != "" {
+			db = db.Where("transaction_type = ?", filter.TransactionType)
+		}
+		if filter.Purpose != "" {
+			db = db.Where("purpose = ?", filter.Purpose)
+		}
+		if filter.MinAmount != nil {
+			db = db.Where("amount >= ?", *filter.MinAmount)
+		}
+		if filter.MaxAmount != nil {
+			db = db.Where("amount <= ?", *filter.MaxAmount)
+		}
+		if filter.From != nil {
+			db = db.Where("created_at >= ?", *filter.From)
+		}
+		if filter.To != nil {
+			// to is a calendar day, so include everything up to the end of it
+			db = db.Where("created_at < ?", filter.To.AddDate(0, 0, 1))
+		}
+		return db
+	})
+}
+
+// redeemQuote locks the quote a conversion transfer settles against, checks it still applies
+// to this transfer and marks it used so it can never settle another one
+func (w *walletService) redeemQuote(t *gorm.DB, body common.CreateTransferRequest, source, destination *domain.Wallet) (*domain.FXQuote, error) {
+	quote, err := w.QuoteRepository.WithTx(t).GetByIDForUpdate(body.QuoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case quote.UsedAt != nil:
+		return nil, domain.ErrQuoteUsed
+	case quote.ExpiresAt.Before(time.Now()):
+		return nil, domain.ErrQuoteExpired
+	case quote.SourceCurrency != source.Currency,
+		quote.DestinationCurrency != destination.Currency,
+		quote.SourceAmount != body.Amount:
+		return nil, domain.ErrQuoteMismatch
+	}
+
+	now := time.Now()
+	quote.UsedAt = &now
+
+	return quote, w.QuoteRepository.WithTx(t).Update(quote)
+}
+
+// counterpart loads the credit leg sharing a transfer reference with debit, leaving out
+// reversals and fees
+func (w *walletService) counterpart(t *gorm.DB, debit *domain.Transaction) (*domain.Transaction, error) {
+	return w.TransactionRepository.WithTx(t).GetBy("reference = ? AND transaction_type = ? AND reversal_of IS NULL AND parent_id IS NULL", debit.Reference, domain.CREDIT)
+}
+
+// PostTransaction posts body against a wallet the caller has already locked within t
+func (w *walletService) PostTransaction(t *gorm.DB, wallet *domain.Wallet, body common.CreateTransactionRequest) (*domain.Transaction, error) {
+	transaction, err := w.ReturnTransaction(wallet, body)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.Reference = body.Reference
+
+	err = w.post(t, wallet, transaction)
+	if err != nil {
+		return nil, err
+	}
+	return transaction, nil
+}
+
+// lockWallets takes a row lock on every wallet in ascending id order, so two
+// transfers touching the same pair of wallets can never deadlock each other
+func (w *walletService) lockWallets(t *gorm.DB, ids ...uuid.UUID) (map[uuid.UUID]*domain.Wallet, error) {
+	sorted := append([]uuid.UUID{}, ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].String() < sorted[j].String() })
+
+	wallets := make(map[uuid.UUID]*domain.Wallet, len(sorted))
+	for _, id := range sorted {
+		// a wallet named twice is locked once, so both names share one copy of its row
+		if _, ok := wallets[id]; ok {
+			continue
+		}
+		wallet, err := w.WalletRepository.WithTx(t).GetByIDForUpdate(id.String())
+		if err != nil {
+			return nil, err
+		}
+		wallets[id] = wallet
+	}
+	return wallets, nil
+}
+
+// post chains transaction onto the wallet's chain head, persists it, journals it in the general
+// ledger and moves the wallet balance and chain head to match it, all within t. The wallet row
+// lock keeps the chain from forking
+func (w *walletService) post(t *gorm.DB, wallet *domain.Wallet, transaction *domain.Transaction) error {
+	transaction.Seal(wallet.ChainHead, []byte(config.Instance.GetLedgerHashKey()))
+
+	err := w.TransactionRepository.WithTx(t).Persist(transaction)
+
+	if err != nil {
+		return err
+	}
+
+	err = w.Ledger.RecordTransaction(t, wallet, transaction)
+
+	if err != nil {
+		return err
+	}
+
+	(*wallet).Balance = transaction.BalanceAfter
+	(*wallet).ChainHead = transaction.Hash
+
+	return w.WalletRepository.WithTx(t).Update(wallet)
+}
+
+func (w *walletService) ReturnTransaction(wallet *domain.Wallet, transaction common.CreateTransactionRequest) (*domain.Transaction, error) {
+	if transaction.Amount <= 0 {
+		return nil, domain.ErrInvalidAmount
+	}
+
+	if transaction.Currency != "" {
+		currency, err := domain.ParseCurrency(transaction.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if currency != wallet.Currency {
+			return nil, domain.ErrCurrencyMismatch
+		}
+	}
+
+	var total int64
+	switch domain.TxnType(transaction.TransactionType) {
+	case domain.CREDIT:
+		if !wallet.Status.AllowsCredit() {
+			return nil, domain.ErrCreditNotAllowed
+		}
+		total = wallet.Balance + transaction.Amount
+	case domain.DEBIT:
+		if !wallet.Status.AllowsDebit() {
+			return nil, domain.ErrDebitNotAllowed
+		}
+		if wallet.Available() < transaction.Amount {
+			return nil, domain.ErrInsufficientBalance
 		}
 		total = wallet.Balance - transaction.Amount
+	default:
+		return nil, domain.ErrInvalidTransactionType
 	}
 	return &domain.Transaction{
 		TransactionType: domain.TxnType(transaction.TransactionType),
 		Purpose:         domain.PurposeType(transaction.Purpose),
 		Amount:          transaction.Amount,
+		Currency:        wallet.Currency,
 		BalanceBefore:   wallet.Balance,
 		BalanceAfter:    total,
 		AccountID:       wallet.AccountID,
+		WalletID:        wallet.ID,
 	}, nil
 }
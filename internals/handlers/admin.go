@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/middleware"
+	"wallet_engine/pkg/utils"
+)
+
+type adminHandler struct {
+	AdminService ports.IAdminService
+	logger       *log.Logger
+	handlerName  string
+}
+
+// NewAdminHandler function creates a new instance for staff admin handler
+func NewAdminHandler(as ports.IAdminService, l *log.Logger, n string) ports.IAdminHandler {
+	return &adminHandler{
+		AdminService: as,
+		logger:       l,
+		handlerName:  n,
+	}
+}
+
+// FreezeWallet godoc
+// @Summary      Freeze a wallet
+// @Description  stop all movement of funds on a wallet. Needs the operator role
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                     true  "Wallet ID"
+// @Param        reason  body      common.AdminReasonRequest  true  "Reason"
+// @Success      200  {object}  common.GetWalletResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /admin/wallets/{id}/freeze [post]
+func (ah *adminHandler) FreezeWallet(c *gin.Context) {
+	ah.setStatus(c, domain.FROZEN)
+}
+
+// UnfreezeWallet godoc
+// @Summary      Unfreeze a wallet
+// @Description  make a frozen wallet active again. Needs the operator role
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                     true  "Wallet ID"
+// @Param        reason  body      common.AdminReasonRequest  true  "Reason"
+// @Success      200  {object}  common.GetWalletResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /admin/wallets/{id}/unfreeze [post]
+func (ah *adminHandler) UnfreezeWallet(c *gin.Context) {
+	ah.setStatus(c, domain.ACTIVE)
+}
+
+func (ah *adminHandler) setStatus(c *gin.Context, next domain.State) {
+	var params common.GetByIDRequest
+	var body common.AdminReasonRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	wallet, err := ah.AdminService.ChangeStatus(params, next, body.Reason, middleware.GetPrincipal(c))
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(wallet, message.GetResponseMessage(ah.handlerName, types.UPDATED)))
+}
+
+// ChangeStatus godoc
+// @Summary      Change a wallet's status
+// @Description  move a wallet to any status its lifecycle allows. Needs the supervisor role
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                     true  "Wallet ID"
+// @Param        status  body      common.AdminStatusRequest  true  "Status"
+// @Success      200  {object}  common.GetWalletResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /admin/wallets/{id}/status [patch]
+func (ah *adminHandler) ChangeStatus(c *gin.Context) {
+	var params common.GetByIDRequest
+	var body common.AdminStatusRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	wallet, err := ah.AdminService.ChangeStatus(params, domain.State(body.Status), body.Reason, middleware.GetPrincipal(c))
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(wallet, message.GetResponseMessage(ah.handlerName, types.UPDATED)))
+}
+
+// AdjustBalance godoc
+// @Summary      Adjust a wallet's balance
+// @Description  credit or debit a wallet by hand against the manual adjustments account. Needs the operator role, and the supervisor role above ADJUSTMENT_SUPERVISOR_THRESHOLD
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string                    true  "Wallet ID"
+// @Param        Idempotency-Key header string false "Replays within the retention window return the original adjustment"
+// @Param        adjustment  body      common.AdjustmentRequest  true  "Adjustment"
+// @Success      201  {object}  common.CreateTransactionResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /admin/wallets/{id}/adjustments [post]
+func (ah *adminHandler) AdjustBalance(c *gin.Context) {
+	var params common.GetByIDRequest
+	var body common.AdjustmentRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	reference, err := idempotencyKey(c, body.Reference)
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	body.Reference = reference
+
+	transaction, err := ah.AdminService.Adjust(params, body, middleware.GetPrincipal(c))
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(transaction, message.GetResponseMessage(ah.handlerName, types.CREATED_TRANSACTION)))
+}
+
+// GetAuditLogs godoc
+// @Summary      List the audit trail
+// @Description  every action staff have taken on wallets, filtered by actor, action or wallet. Needs the viewer role
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        actor        query     string  false  "Actor"
+// @Param        action       query     string  false  "Action"
+// @Param        resource_id  query     string  false  "Wallet ID"
+// @Param        page         query     int     false  "Page"
+// @Param        limit        query     int     false  "Limit"
+// @Success      200  {object}  common.GetAuditLogsResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /admin/audit-logs [get]
+func (ah *adminHandler) GetAuditLogs(c *gin.Context) {
+	var filter common.AuditLogFilterRequest
+	var pagination utils.Pagination
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	logs, err := ah.AdminService.GetAuditLogs(filter, &pagination)
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(logs, message.GetResponseMessage(ah.handlerName, types.OKAY)))
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/services"
+	"wallet_engine/internals/middleware"
+	"wallet_engine/internals/repositories"
+)
+
+func TestAdminHandler_Roles(t *testing.T) {
+	adminService := services.NewAdminService(*walletRepository, *repositories.NewRepository[domain.AuditLog](DBConnection), walletService, 1000, logging, DBConnection)
+	adminHandler := NewAdminHandler(adminService, logging, "Wallet")
+
+	r := SetupRouter()
+	staff := r.Group("/v1/admin", func(c *gin.Context) {
+		middleware.SetPrincipal(c, &domain.Principal{Subject: c.GetHeader("X-Actor"), Role: domain.Role(c.GetHeader("X-Role"))})
+	}, middleware.RequireRole(domain.VIEWER_ROLE))
+	staff.GET("/audit-logs", adminHandler.GetAuditLogs)
+	staff.POST("/wallets/:id/freeze", middleware.RequireRole(domain.OPERATOR_ROLE), adminHandler.FreezeWallet)
+	staff.POST("/wallets/:id/unfreeze", middleware.RequireRole(domain.OPERATOR_ROLE), adminHandler.UnfreezeWallet)
+	staff.POST("/wallets/:id/adjustments", middleware.RequireRole(domain.OPERATOR_ROLE), adminHandler.AdjustBalance)
+
+	serve := func(role domain.Role, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonValue, _ := json.Marshal(body)
+		request, err := http.NewRequest(method, path, bytes.NewBuffer(jsonValue))
+		require.NoError(t, err)
+		request.Header.Set("X-Actor", fmt.Sprintf("%v@example.com", role))
+		request.Header.Set("X-Role", string(role))
+
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+
+	wallet := createWallet(t).Data
+	reason := common.AdminReasonRequest{Reason: "suspected fraud"}
+
+	require.Equal(t, http.StatusForbidden, serve("", "POST", fmt.Sprintf("/v1/admin/wallets/%v/freeze", wallet.ID), reason).Code)
+	require.Equal(t, http.StatusForbidden, serve(domain.VIEWER_ROLE, "POST", fmt.Sprintf("/v1/admin/wallets/%v/freeze", wallet.ID), reason).Code)
+
+	response := serve(domain.OPERATOR_ROLE, "POST", fmt.Sprintf("/v1/admin/wallets/%v/freeze", wallet.ID), reason)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var frozen common.CreateWalletResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &frozen))
+	require.Equal(t, domain.FROZEN, frozen.Data.Status)
+
+	response = serve(domain.VIEWER_ROLE, "GET", fmt.Sprintf("/v1/admin/audit-logs?resource_id=%v", wallet.ID), nil)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var logs common.GetAuditLogsResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &logs))
+	require.Len(t, logs.Data.Rows, 1)
+	require.Equal(t, "operator@example.com", logs.Data.Rows[0].Actor)
+	require.Equal(t, domain.OPERATOR_ROLE, logs.Data.Rows[0].Role)
+	require.Equal(t, domain.WALLET_STATUS_CHANGED, logs.Data.Rows[0].Action)
+	require.Equal(t, "suspected fraud", logs.Data.Rows[0].Reason)
+
+	require.Equal(t, http.StatusOK, serve(domain.OPERATOR_ROLE, "POST", fmt.Sprintf("/v1/admin/wallets/%v/unfreeze", wallet.ID), reason).Code)
+
+	small := common.AdjustmentRequest{TransactionType: "credit", Amount: 1000, Reason: "goodwill"}
+	large := common.AdjustmentRequest{TransactionType: "credit", Amount: 1001, Reason: "missed settlement"}
+
+	require.Equal(t, http.StatusCreated, serve(domain.OPERATOR_ROLE, "POST", fmt.Sprintf("/v1/admin/wallets/%v/adjustments", wallet.ID), small).Code)
+	require.Equal(t, http.StatusForbidden, serve(domain.OPERATOR_ROLE, "POST", fmt.Sprintf("/v1/admin/wallets/%v/adjustments", wallet.ID), large).Code)
+
+	response = serve(domain.SUPERVISOR_ROLE, "POST", fmt.Sprintf("/v1/admin/wallets/%v/adjustments", wallet.ID), large)
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var adjustment common.CreateTransactionResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &adjustment))
+	require.Equal(t, domain.PurposeType(domain.ADJUSTMENT), adjustment.Data.Purpose)
+	require.Equal(t, int64(2001), adjustment.Data.BalanceAfter)
+
+	response = serve(domain.VIEWER_ROLE, "GET", fmt.Sprintf("/v1/admin/audit-logs?resource_id=%v&action=%v", wallet.ID, domain.WALLET_ADJUSTED), nil)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &logs))
+	require.Len(t, logs.Data.Rows, 2)
+
+	// a retried adjustment returns the original rather than posting twice
+	keyed := common.AdjustmentRequest{TransactionType: "credit", Amount: 500, Reason: "retried", Reference: uuid.NewV4().String()}
+	response = serve(domain.OPERATOR_ROLE, "POST", fmt.Sprintf("/v1/admin/wallets/%v/adjustments", wallet.ID), keyed)
+	require.Equal(t, http.StatusCreated, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &adjustment))
+
+	response = serve(domain.OPERATOR_ROLE, "POST", fmt.Sprintf("/v1/admin/wallets/%v/adjustments", wallet.ID), keyed)
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var replayed common.CreateTransactionResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &replayed))
+	require.Equal(t, adjustment.Data.ID, replayed.Data.ID)
+
+	stored, err := walletService.GetWalletByID(wallet.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, int64(2501), stored.Balance)
+
+	// a key already spent on a transfer cannot be reused for an adjustment
+	destination := createWallet(t).Data
+	spent := uuid.NewV4().String()
+	require.Equal(t, http.StatusCreated, transfer(t, common.CreateTransferRequest{SourceWalletID: wallet.ID.String(), DestinationWalletID: destination.ID.String(), Amount: 100, Reference: spent}).Code)
+
+	keyed.Reference = spent
+	require.Equal(t, http.StatusUnprocessableEntity, serve(domain.OPERATOR_ROLE, "POST", fmt.Sprintf("/v1/admin/wallets/%v/adjustments", wallet.ID), keyed).Code)
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/middleware"
+)
+
+type apiKeyHandler struct {
+	APIKeyService ports.IAPIKeyService
+	logger        *log.Logger
+	handlerName   string
+}
+
+// NewAPIKeyHandler function creates a new instance for API key handler
+func NewAPIKeyHandler(as ports.IAPIKeyService, l *log.Logger, n string) ports.IAPIKeyHandler {
+	return &apiKeyHandler{
+		APIKeyService: as,
+		logger:        l,
+		handlerName:   n,
+	}
+}
+
+// CreateAPIKey godoc
+// @Summary      Issue an API key
+// @Description  issue a scoped API key for a backend service. The key is only ever shown in this response
+// @Tags         api-key
+// @Accept       json
+// @Produce      json
+// @Param        key  body      common.CreateAPIKeyRequest  true  "API key"
+// @Success      201  {object}  common.CreateAPIKeyResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api-keys [post]
+func (ah *apiKeyHandler) CreateAPIKey(c *gin.Context) {
+	var body common.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	actor := ""
+	if principal := middleware.GetPrincipal(c); principal != nil {
+		actor = principal.Subject
+	}
+
+	issued, err := ah.APIKeyService.CreateAPIKey(body, actor)
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(issued, message.GetResponseMessage(ah.handlerName, types.CREATED)))
+}
+
+// GetAPIKeys godoc
+// @Summary      List API keys
+// @Description  every API key issued, revoked ones included, newest first
+// @Tags         api-key
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  common.GetAPIKeysResponse
+// @Failure      403  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api-keys [get]
+func (ah *apiKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys, err := ah.APIKeyService.GetAPIKeys()
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(keys, message.GetResponseMessage(ah.handlerName, types.OKAY)))
+}
+
+// RevokeAPIKey godoc
+// @Summary      Revoke an API key
+// @Description  stop an API key from authenticating any further request
+// @Tags         api-key
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "API key ID"
+// @Success      200  {object}  common.GetAPIKeyResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /api-keys/{id}/revoke [post]
+func (ah *apiKeyHandler) RevokeAPIKey(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	apiKey, err := ah.APIKeyService.RevokeAPIKey(params)
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(apiKey, message.GetResponseMessage(ah.handlerName, types.REVOKED)))
+}
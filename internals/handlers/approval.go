@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/middleware"
+	"wallet_engine/pkg/utils"
+)
+
+type approvalHandler struct {
+	ApprovalService ports.IApprovalService
+	logger          *log.Logger
+	handlerName     string
+}
+
+// NewApprovalHandler function creates a new instance for approval handler
+func NewApprovalHandler(as ports.IApprovalService, l *log.Logger, n string) ports.IApprovalHandler {
+	return &approvalHandler{
+		ApprovalService: as,
+		logger:          l,
+		handlerName:     n,
+	}
+}
+
+// GetApprovals godoc
+// @Summary      List the approvals queue
+// @Description  debits held for a second person's approval, oldest first. Only pending requests are listed unless a status is given. Needs the viewer role
+// @Tags         approval
+// @Accept       json
+// @Produce      json
+// @Param        status     query     string  false  "pending_approval, approved, rejected or expired"
+// @Param        wallet_id  query     string  false  "Wallet ID"
+// @Param        maker      query     string  false  "Maker"
+// @Param        page       query     int     false  "Page"
+// @Param        limit      query     int     false  "Limit"
+// @Success      200  {object}  common.GetApprovalsResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /admin/approvals [get]
+func (ah *approvalHandler) GetApprovals(c *gin.Context) {
+	var filter common.ApprovalFilterRequest
+	var pagination utils.Pagination
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	approvals, err := ah.ApprovalService.GetApprovals(filter, &pagination)
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(approvals, message.GetResponseMessage(ah.handlerName, types.OKAY)))
+}
+
+// GetApproval godoc
+// @Summary      Get an approval request
+// @Description  get a debit held for approval, with who made it and who decided on it. Needs the viewer role
+// @Tags         approval
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Approval request ID"
+// @Success      200  {object}  common.GetApprovalResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /admin/approvals/{id} [get]
+func (ah *approvalHandler) GetApproval(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	approval, err := ah.ApprovalService.GetApproval(params.ID)
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(approval, message.GetResponseMessage(ah.handlerName, types.OKAY)))
+}
+
+// Approve godoc
+// @Summary      Approve a held debit
+// @Description  post a debit held for approval. The checker must not be its maker. Needs the operator role
+// @Tags         approval
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Approval request ID"
+// @Success      200  {object}  common.ApproveResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /admin/approvals/{id}/approve [post]
+func (ah *approvalHandler) Approve(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	approval, transaction, err := ah.ApprovalService.Approve(params, middleware.GetPrincipal(c))
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	approved := common.ApprovalResult{
+		Approval:    *approval,
+		Transaction: transaction,
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(approved, message.GetResponseMessage(ah.handlerName, types.APPROVED)))
+}
+
+// Reject godoc
+// @Summary      Reject a held debit
+// @Description  turn down a debit held for approval and give its funds back. The checker must not be its maker. Needs the operator role
+// @Tags         approval
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                     true  "Approval request ID"
+// @Param        reason  body      common.AdminReasonRequest  true  "Reason"
+// @Success      200  {object}  common.GetApprovalResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /admin/approvals/{id}/reject [post]
+func (ah *approvalHandler) Reject(c *gin.Context) {
+	var params common.GetByIDRequest
+	var body common.AdminReasonRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ah.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	approval, err := ah.ApprovalService.Reject(params, body.Reason, middleware.GetPrincipal(c))
+	if err != nil {
+		ah.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(approval, message.GetResponseMessage(ah.handlerName, types.REJECTED)))
+}
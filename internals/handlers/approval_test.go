@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/services"
+	"wallet_engine/internals/middleware"
+	"wallet_engine/internals/repositories"
+	"wallet_engine/pkg/config"
+)
+
+func TestApprovalHandler_MakerChecker(t *testing.T) {
+	threshold := "1000"
+	previous := config.Instance
+	config.Instance = &config.Config{ApprovalThreshold: &threshold}
+	t.Cleanup(func() { config.Instance = previous })
+
+	approvalService := services.NewApprovalService(*walletRepository, *approvalRepository, *repositories.NewRepository[domain.AuditLog](DBConnection), walletService, holdService, logging, DBConnection)
+	approvalHandler := NewApprovalHandler(approvalService, logging, "Approval request")
+
+	r := SetupRouter()
+	v1 := r.Group("/v1", func(c *gin.Context) {
+		middleware.SetPrincipal(c, &domain.Principal{Subject: c.GetHeader("X-Actor"), Role: domain.Role(c.GetHeader("X-Role"))})
+	})
+	v1.PATCH("/wallet/:id", handler.TransactionWallet)
+	staff := v1.Group("/admin", middleware.RequireRole(domain.VIEWER_ROLE))
+	staff.GET("/approvals", approvalHandler.GetApprovals)
+	staff.POST("/approvals/:id/approve", middleware.RequireRole(domain.OPERATOR_ROLE), approvalHandler.Approve)
+	staff.POST("/approvals/:id/reject", middleware.RequireRole(domain.OPERATOR_ROLE), approvalHandler.Reject)
+
+	serve := func(actor string, role domain.Role, method, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonValue, _ := json.Marshal(body)
+		request, err := http.NewRequest(method, path, bytes.NewBuffer(jsonValue))
+		require.NoError(t, err)
+		request.Header.Set("X-Actor", actor)
+		request.Header.Set("X-Role", string(role))
+
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+
+	wallet := createWallet(t).Data
+	creditWallet(t, wallet.ID.String(), 5000)
+
+	debit := func(amount int64) *httptest.ResponseRecorder {
+		return serve("maker@example.com", "", "PATCH", fmt.Sprintf("/v1/wallet/%v", wallet.ID), common.CreateTransactionRequest{
+			TransactionType: "debit",
+			Purpose:         "withdrawal",
+			Amount:          amount,
+			AccountID:       wallet.ID.String(),
+		})
+	}
+
+	held := func() (int64, int64) {
+		found, err := walletService.GetWalletByID(wallet.ID.String())
+		require.NoError(t, err)
+		return found.Balance, found.HeldBalance
+	}
+
+	require.Equal(t, http.StatusOK, debit(500).Code)
+
+	response := debit(3000)
+	require.Equal(t, http.StatusAccepted, response.Code)
+
+	var pending common.GetApprovalResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &pending))
+	require.Equal(t, domain.APPROVAL_PENDING, pending.Data.Status)
+	require.Equal(t, "maker@example.com", pending.Data.Maker)
+
+	balance, onHold := held()
+	require.Equal(t, int64(4500), balance)
+	require.Equal(t, int64(3000), onHold)
+
+	response = serve("checker@example.com", domain.VIEWER_ROLE, "GET", fmt.Sprintf("/v1/admin/approvals?wallet_id=%v", wallet.ID), nil)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var queue common.GetApprovalsResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &queue))
+	require.Len(t, queue.Data.Rows, 1)
+	require.Equal(t, pending.Data.ID, queue.Data.Rows[0].ID)
+
+	approve := fmt.Sprintf("/v1/admin/approvals/%v/approve", pending.Data.ID)
+	require.Equal(t, http.StatusForbidden, serve("checker@example.com", domain.VIEWER_ROLE, "POST", approve, nil).Code)
+	require.Equal(t, http.StatusForbidden, serve("maker@example.com", domain.OPERATOR_ROLE, "POST", approve, nil).Code)
+
+	response = serve("checker@example.com", domain.OPERATOR_ROLE, "POST", approve, nil)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var approved common.ApproveResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &approved))
+	require.Equal(t, domain.APPROVAL_APPROVED, approved.Data.Approval.Status)
+	require.Equal(t, "checker@example.com", approved.Data.Approval.Checker)
+	require.Equal(t, int64(3000), approved.Data.Transaction.Amount)
+
+	balance, onHold = held()
+	require.Equal(t, int64(1500), balance)
+	require.Equal(t, int64(0), onHold)
+
+	require.Equal(t, http.StatusUnprocessableEntity, serve("checker@example.com", domain.OPERATOR_ROLE, "POST", approve, nil).Code)
+
+	response = debit(1200)
+	require.Equal(t, http.StatusAccepted, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &pending))
+
+	response = serve("checker@example.com", domain.OPERATOR_ROLE, "POST", fmt.Sprintf("/v1/admin/approvals/%v/reject", pending.Data.ID), common.AdminReasonRequest{Reason: "not verified"})
+	require.Equal(t, http.StatusOK, response.Code)
+
+	balance, onHold = held()
+	require.Equal(t, int64(1500), balance)
+	require.Equal(t, int64(0), onHold)
+
+	response = debit(1200)
+	require.Equal(t, http.StatusAccepted, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &pending))
+
+	lapsed, err := approvalRepository.GetByID(pending.Data.ID.String())
+	require.NoError(t, err)
+	lapsed.ExpiresAt = time.Now().Add(-time.Minute)
+	require.NoError(t, approvalRepository.Update(lapsed))
+
+	expired, err := approvalService.ExpireApprovals()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, expired, 1)
+
+	lapsed, err = approvalRepository.GetByID(pending.Data.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, domain.APPROVAL_EXPIRED, lapsed.Status)
+
+	balance, onHold = held()
+	require.Equal(t, int64(1500), balance)
+	require.Equal(t, int64(0), onHold)
+
+	// callers inside the engine skip request binding, a debit spelt in another case is still gated
+	_, err = walletService.CreateTransaction(common.GetByIDRequest{ID: wallet.ID.String()}, common.CreateTransactionRequest{
+		TransactionType: "DEBIT",
+		Purpose:         "withdrawal",
+		Amount:          1200,
+		AccountID:       wallet.ID.String(),
+	}, "maker@example.com")
+	var queued *domain.PendingApprovalError
+	require.ErrorAs(t, err, &queued)
+
+	balance, onHold = held()
+	require.Equal(t, int64(1500), balance)
+	require.Equal(t, int64(1200), onHold)
+}
+
+func TestApprovalHandler_EveryDebitPath(t *testing.T) {
+	threshold := "1000"
+	previous := config.Instance
+	config.Instance = &config.Config{ApprovalThreshold: &threshold}
+	t.Cleanup(func() { config.Instance = previous })
+
+	approvalService := services.NewApprovalService(*walletRepository, *approvalRepository, *repositories.NewRepository[domain.AuditLog](DBConnection), walletService, holdService, logging, DBConnection)
+	approvalHandler := NewApprovalHandler(approvalService, logging, "Approval request")
+
+	r := SetupRouter()
+	v1 := r.Group("/v1", func(c *gin.Context) {
+		middleware.SetPrincipal(c, &domain.Principal{Subject: c.GetHeader("X-Actor"), Scopes: []string{domain.ADMIN_SCOPE}, Role: domain.Role(c.GetHeader("X-Role"))})
+	})
+	v1.POST("/transfers", handler.Transfer)
+	v1.POST("/wallet/:id/holds", holdsHandler.CreateHold)
+	v1.POST("/wallet/:id/close", handler.CloseWallet)
+	v1.POST("/admin/approvals/:id/approve", approvalHandler.Approve)
+
+	serve := func(actor string, role domain.Role, path string, body interface{}) *httptest.ResponseRecorder {
+		jsonValue, _ := json.Marshal(body)
+		request, err := http.NewRequest("POST", path, bytes.NewBuffer(jsonValue))
+		require.NoError(t, err)
+		request.Header.Set("X-Actor", actor)
+		request.Header.Set("X-Role", string(role))
+
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+
+	// each path answers 202 with the request, which a checker then approves
+	held := func(path string, body interface{}) common.ApprovalResult {
+		response := serve("maker@example.com", "", path, body)
+		require.Equal(t, http.StatusAccepted, response.Code, response.Body.String())
+
+		var pending common.GetApprovalResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &pending))
+		require.Equal(t, "maker@example.com", pending.Data.Maker)
+
+		response = serve("checker@example.com", domain.OPERATOR_ROLE, fmt.Sprintf("/v1/admin/approvals/%v/approve", pending.Data.ID), nil)
+		require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+		var approved common.ApproveResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &approved))
+		return approved.Data
+	}
+
+	balances := func(id string) (int64, int64) {
+		found, err := walletService.GetWalletByID(id)
+		require.NoError(t, err)
+		return found.Balance, found.HeldBalance
+	}
+
+	source := createWallet(t).Data
+	destination := createWallet(t).Data
+	creditWallet(t, source.ID.String(), 10000)
+
+	transfer := common.CreateTransferRequest{
+		SourceWalletID:      source.ID.String(),
+		DestinationWalletID: destination.ID.String(),
+		Amount:              3000,
+	}
+	response := serve("maker@example.com", "", "/v1/transfers", transfer)
+	require.Equal(t, http.StatusAccepted, response.Code)
+
+	balance, onHold := balances(source.ID.String())
+	require.Equal(t, int64(10000), balance)
+	require.Equal(t, int64(3000), onHold)
+
+	var pending common.GetApprovalResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &pending))
+	require.Equal(t, domain.APPROVAL_TRANSFER, pending.Data.Kind)
+
+	response = serve("checker@example.com", domain.OPERATOR_ROLE, fmt.Sprintf("/v1/admin/approvals/%v/approve", pending.Data.ID), nil)
+	require.Equal(t, http.StatusOK, response.Code, response.Body.String())
+
+	balance, onHold = balances(source.ID.String())
+	require.Equal(t, int64(7000), balance)
+	require.Equal(t, int64(0), onHold)
+
+	balance, _ = balances(destination.ID.String())
+	require.Equal(t, int64(3000), balance)
+
+	approved := held(fmt.Sprintf("/v1/wallet/%v/holds", source.ID), common.CreateHoldRequest{Amount: 2000})
+	require.Nil(t, approved.Transaction)
+	require.NotNil(t, approved.Approval.HoldID)
+
+	hold, err := holdService.GetHoldByID(approved.Approval.HoldID.String())
+	require.NoError(t, err)
+	require.Equal(t, domain.HOLD_ACTIVE, hold.Status)
+	require.Equal(t, int64(2000), hold.Amount)
+
+	balance, onHold = balances(source.ID.String())
+	require.Equal(t, int64(7000), balance)
+	require.Equal(t, int64(2000), onHold)
+
+	closing := createWallet(t).Data
+	creditWallet(t, closing.ID.String(), 4000)
+
+	approved = held(fmt.Sprintf("/v1/wallet/%v/close", closing.ID), common.CloseWalletRequest{DestinationWalletID: destination.ID.String(), Actor: "checker@example.com"})
+	require.NotNil(t, approved.Transaction)
+	require.Equal(t, int64(4000), approved.Transaction.Amount)
+
+	closed, err := walletService.GetWalletByID(closing.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, domain.CLOSED, closed.Status)
+	require.Equal(t, int64(0), closed.Balance)
+
+	balance, _ = balances(destination.ID.String())
+	require.Equal(t, int64(7000), balance)
+}
+
+func TestApprovalHandler_DefaultThreshold(t *testing.T) {
+	previous := config.Instance
+	config.Instance = &config.Config{}
+	t.Cleanup(func() { config.Instance = previous })
+
+	threshold := config.Instance.GetApprovalThreshold()
+	for _, limit := range domain.DefaultTierLimits {
+		require.Less(t, threshold, limit.MaxSingleTransaction, "tier %v refuses a debit before it could be held for approval", limit.Tier)
+	}
+
+	wallet := createWallet(t).Data
+	creditWallet(t, wallet.ID.String(), 2*threshold+1)
+
+	r := SetupRouter()
+	r.PATCH("/v1/wallet/:id", handler.TransactionWallet)
+
+	debit := func(amount int64) int {
+		jsonValue, _ := json.Marshal(common.CreateTransactionRequest{
+			TransactionType: "debit",
+			Purpose:         "withdrawal",
+			Amount:          amount,
+			AccountID:       wallet.ID.String(),
+		})
+		request, err := http.NewRequest("PATCH", fmt.Sprintf("/v1/wallet/%v", wallet.ID), bytes.NewBuffer(jsonValue))
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response.Code
+	}
+
+	require.Equal(t, http.StatusOK, debit(threshold))
+	require.Equal(t, http.StatusAccepted, debit(threshold+1))
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/services"
+	"wallet_engine/internals/middleware"
+	"wallet_engine/internals/repositories"
+	"wallet_engine/pkg/token"
+)
+
+func TestMiddleware_Authenticate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	verifier, err := token.NewVerifier("secret", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), "", "")
+	require.NoError(t, err)
+
+	sign := func(method jwt.SigningMethod, key interface{}, subject, scope string, expires time.Duration) string {
+		signed, err := jwt.NewWithClaims(method, token.Claims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   subject,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(expires)),
+			},
+			Scope: scope,
+		}).SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+
+	wallet := createWallet(t).Data
+	owner := wallet.Owner.String()
+
+	apiKeys := services.NewAPIKeyService(*repositories.NewRepository[domain.APIKey](DBConnection), logging)
+	ownsWallet := middleware.OwnsResource(middleware.Param("id"), func(id string) (uuid.UUID, error) {
+		found, err := walletService.GetWalletByID(id)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return found.Owner, nil
+	})
+
+	r := SetupRouter()
+	v1 := r.Group("/v1", middleware.Authenticate(verifier, apiKeys, logging))
+	v1.GET("/wallet/:id", middleware.RequireScope(domain.WALLET_READ_SCOPE), ownsWallet, handler.GetWalletByID)
+	v1.GET("/wallet/:id/status-history", middleware.RequireScope(domain.WALLET_READ_SCOPE), ownsWallet, handler.GetStatusChanges)
+	v1.DELETE("/wallet/:id", middleware.RequireScope(domain.WALLET_WRITE_SCOPE), ownsWallet, handler.DeleteWallet)
+
+	serve := func(method, path string, headers map[string]string) int {
+		request, err := http.NewRequest(method, path, nil)
+		require.NoError(t, err)
+		for name, value := range headers {
+			request.Header.Set(name, value)
+		}
+
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response.Code
+	}
+
+	get := func(bearer string) int {
+		if bearer == "" {
+			return serve("GET", "/v1/wallet/"+wallet.ID.String(), nil)
+		}
+		return serve("GET", "/v1/wallet/"+wallet.ID.String(), map[string]string{"Authorization": "Bearer " + bearer})
+	}
+
+	hs256 := []byte("secret")
+	require.Equal(t, http.StatusUnauthorized, get(""))
+	require.Equal(t, http.StatusUnauthorized, get(sign(jwt.SigningMethodHS256, []byte("wrong"), owner, "", time.Hour)))
+	require.Equal(t, http.StatusUnauthorized, get(sign(jwt.SigningMethodHS256, hs256, owner, "", -time.Minute)))
+	require.Equal(t, http.StatusForbidden, get(sign(jwt.SigningMethodHS256, hs256, uuid.NewV4().String(), "", time.Hour)))
+	require.Equal(t, http.StatusOK, get(sign(jwt.SigningMethodHS256, hs256, owner, "", time.Hour)))
+	require.Equal(t, http.StatusOK, get(sign(jwt.SigningMethodRS256, key, owner, "", time.Hour)))
+	require.Equal(t, http.StatusOK, get(sign(jwt.SigningMethodHS256, hs256, uuid.NewV4().String(), "admin", time.Hour)))
+
+	forever, err := jwt.NewWithClaims(jwt.SigningMethodHS256, token.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: owner},
+	}).SignedString(hs256)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, get(forever))
+
+	customer := map[string]string{"Authorization": "Bearer " + sign(jwt.SigningMethodHS256, hs256, owner, "", time.Hour)}
+	require.Equal(t, http.StatusForbidden, serve("GET", "/v1/wallet/"+uuid.NewV4().String(), customer))
+	require.Equal(t, http.StatusForbidden, serve("GET", "/v1/wallet/not-a-wallet", customer))
+
+	issued, err := apiKeys.CreateAPIKey(common.CreateAPIKeyRequest{Name: "payments", Scopes: []string{domain.WALLET_READ_SCOPE}}, "ops")
+	require.NoError(t, err)
+
+	withKey := map[string]string{middleware.APIKeyHeader: issued.Key}
+	require.Equal(t, http.StatusOK, serve("GET", "/v1/wallet/"+wallet.ID.String(), withKey))
+	require.Equal(t, http.StatusOK, serve("GET", "/v1/wallet/"+wallet.ID.String()+"/status-history", withKey))
+	require.Equal(t, http.StatusForbidden, serve("DELETE", "/v1/wallet/"+wallet.ID.String(), withKey))
+	require.Equal(t, http.StatusUnauthorized, serve("GET", "/v1/wallet/"+wallet.ID.String(), map[string]string{middleware.APIKeyHeader: issued.Key + "0"}))
+
+	keys, err := apiKeys.GetAPIKeys()
+	require.NoError(t, err)
+	for _, key := range keys {
+		if key.ID == issued.APIKey.ID {
+			require.NotNil(t, key.LastUsedAt)
+			require.NotEqual(t, issued.Key, key.Hash)
+		}
+	}
+
+	_, err = apiKeys.RevokeAPIKey(common.GetByIDRequest{ID: issued.APIKey.ID.String()})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, serve("GET", "/v1/wallet/"+wallet.ID.String(), withKey))
+
+	_, err = apiKeys.RevokeAPIKey(common.GetByIDRequest{ID: issued.APIKey.ID.String()})
+	require.ErrorIs(t, err, domain.ErrAPIKeyRevoked)
+}
+
+func TestWalletHandler_OwnershipOfBoundBody(t *testing.T) {
+	victim := createWallet(t).Data
+	own := createWallet(t).Data
+	creditWallet(t, victim.ID.String(), 1000)
+	creditWallet(t, own.ID.String(), 1000)
+
+	r := SetupRouter()
+	v1 := r.Group("/v1", func(c *gin.Context) {
+		middleware.SetPrincipal(c, &domain.Principal{Subject: own.Owner.String()})
+	})
+	v1.POST("/transfers", handler.Transfer)
+	v1.POST("/wallet", handler.CreateWallet)
+
+	post := func(path string, body string) int {
+		request, err := http.NewRequest("POST", path, strings.NewReader(body))
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response.Code
+	}
+
+	// encoding/json binds keys case-insensitively, so the check must run against the bound body
+	require.Equal(t, http.StatusForbidden, post("/v1/transfers", fmt.Sprintf(`{"source_wallet_id":"%v","destination_wallet_id":"%v","amount":100}`, victim.ID, own.ID)))
+	require.Equal(t, http.StatusForbidden, post("/v1/transfers", fmt.Sprintf(`{"Source_Wallet_ID":"%v","destination_wallet_id":"%v","amount":100}`, victim.ID, own.ID)))
+	require.Equal(t, http.StatusCreated, post("/v1/transfers", fmt.Sprintf(`{"Source_Wallet_ID":"%v","destination_wallet_id":"%v","amount":100}`, own.ID, victim.ID)))
+
+	require.Equal(t, http.StatusForbidden, post("/v1/wallet", fmt.Sprintf(`{"Customer_ID":"%v","status":"active"}`, victim.Owner)))
+	require.Equal(t, http.StatusCreated, post("/v1/wallet", fmt.Sprintf(`{"customer_id":"%v","status":"active"}`, own.Owner)))
+}
+
+func TestWalletHandler_OnlyServicesAndAdminsCredit(t *testing.T) {
+	wallet := createWallet(t).Data
+	id := wallet.ID.String()
+	creditWallet(t, id, 1000)
+
+	r := SetupRouter()
+	v1 := r.Group("/v1", func(c *gin.Context) {
+		principal := &domain.Principal{Subject: wallet.Owner.String()}
+		if c.GetHeader("X-Service") != "" {
+			principal = &domain.Principal{Subject: "apikey:test", Scopes: []string{domain.TRANSACTION_WRITE_SCOPE}, Service: true}
+		}
+		middleware.SetPrincipal(c, principal)
+	})
+	v1.PATCH("/wallet/:id", handler.TransactionWallet)
+
+	patch := func(transactionType, purpose string, service bool) int {
+		body := fmt.Sprintf(`{"transaction_type":"%v","purpose":"%v","amount":100,"account_id":"%v"}`, transactionType, purpose, id)
+		request, err := http.NewRequest("PATCH", "/v1/wallet/"+id, strings.NewReader(body))
+		require.NoError(t, err)
+		if service {
+			request.Header.Set("X-Service", "true")
+		}
+
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response.Code
+	}
+
+	// a customer may spend what they hold but cannot mint funds into their own wallet
+	require.Equal(t, http.StatusForbidden, patch("credit", "deposit", false))
+	require.Equal(t, http.StatusOK, patch("debit", "withdrawal", false))
+	require.Equal(t, http.StatusOK, patch("credit", "deposit", true))
+
+	stored, err := walletService.GetWalletByID(id)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), stored.Balance)
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/pkg/utils"
+)
+
+type customerHandler struct {
+	CustomerService ports.ICustomerService
+	logger          *log.Logger
+	handlerName     string
+}
+
+// NewCustomerHandler function creates a new instance for customer handler
+func NewCustomerHandler(cs ports.ICustomerService, l *log.Logger, n string) ports.ICustomerHandler {
+	return &customerHandler{
+		CustomerService: cs,
+		logger:          l,
+		handlerName:     n,
+	}
+}
+
+// CreateCustomer godoc
+// @Summary      Create customer
+// @Description  register a customer who can then open wallets
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        customer  body      common.CreateCustomerRequest  true  "Customer"
+// @Success      201  {object}  common.GetCustomerResponse
+// @Failure      400  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /customers [post]
+func (ch *customerHandler) CreateCustomer(c *gin.Context) {
+	var body common.CreateCustomerRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	customer, err := ch.CustomerService.CreateCustomer(body)
+	if err != nil {
+		ch.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(customer, message.GetResponseMessage(ch.handlerName, types.CREATED)))
+}
+
+// GetCustomers godoc
+// @Summary      List customers
+// @Description  paginated customers
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        limit  query  int  false  "Page size"
+// @Param        page   query  int  false  "Page number"
+// @Success      200  {object}  common.GetCustomersResponse
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /customers [get]
+func (ch *customerHandler) GetCustomers(c *gin.Context) {
+	var pagination utils.Pagination
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	customers, err := ch.CustomerService.GetCustomers(&pagination)
+	if err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(customers, message.GetResponseMessage(ch.handlerName, types.OKAY)))
+}
+
+// GetCustomer godoc
+// @Summary      Get customer
+// @Description  get a customer by id
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Customer ID"
+// @Success      200  {object}  common.GetCustomerResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /customers/{id} [get]
+func (ch *customerHandler) GetCustomer(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	customer, err := ch.CustomerService.GetCustomer(params)
+	if err != nil {
+		ch.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(customer, message.GetResponseMessage(ch.handlerName, types.OKAY)))
+}
+
+// UpdateCustomer godoc
+// @Summary      Update customer
+// @Description  change a customer's details, KYC tier or status. A new tier moves every wallet they hold onto it
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string                        true  "Customer ID"
+// @Param        customer  body      common.UpdateCustomerRequest  true  "Fields to change"
+// @Success      200  {object}  common.GetCustomerResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /customers/{id} [patch]
+func (ch *customerHandler) UpdateCustomer(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	var body common.UpdateCustomerRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	customer, err := ch.CustomerService.UpdateCustomer(params, body)
+	if err != nil {
+		ch.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(customer, message.GetResponseMessage(ch.handlerName, types.UPDATED)))
+}
+
+// DeleteCustomer godoc
+// @Summary      Delete customer
+// @Description  remove a customer who no longer holds any wallet
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Customer ID"
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /customers/{id} [delete]
+func (ch *customerHandler) DeleteCustomer(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := ch.CustomerService.DeleteCustomer(params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, result.ReturnSuccessMessage(types.DELETED))
+}
+
+// GetCustomerWallets godoc
+// @Summary      List a customer's wallets
+// @Description  every wallet the customer holds, oldest first
+// @Tags         customer
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Customer ID"
+// @Success      200  {object}  common.GetCustomerWalletsResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /customers/{id}/wallets [get]
+func (ch *customerHandler) GetCustomerWallets(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ch.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	wallets, err := ch.CustomerService.GetCustomerWallets(params)
+	if err != nil {
+		ch.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(wallets, message.GetResponseMessage(ch.handlerName, types.OKAY)))
+}
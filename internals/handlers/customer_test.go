@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/services"
+)
+
+var customersHandler = NewCustomerHandler(services.NewCustomerService(*customerRepository, *walletRepository, logging, DBConnection), logging, "Customer")
+
+func serveCustomer(t *testing.T, method, url string, body interface{}) *httptest.ResponseRecorder {
+	r := SetupRouter()
+	r.POST("/v1/customers", customersHandler.CreateCustomer)
+	r.GET("/v1/customers/:id", customersHandler.GetCustomer)
+	r.PATCH("/v1/customers/:id", customersHandler.UpdateCustomer)
+	r.DELETE("/v1/customers/:id", customersHandler.DeleteCustomer)
+	r.GET("/v1/customers/:id/wallets", customersHandler.GetCustomerWallets)
+
+	jsonValue, _ := json.Marshal(body)
+	request, err := http.NewRequest(method, url, bytes.NewBuffer(jsonValue))
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	return response
+}
+
+func createCustomer(t *testing.T) domain.Customer {
+	response := serveCustomer(t, "POST", "/v1/customers", common.CreateCustomerRequest{
+		Name:  "Ada Obi",
+		Email: fmt.Sprintf("%v@example.com", uuid.NewV4()),
+	})
+	require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+	var body common.GetCustomerResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	return body.Data
+}
+
+func TestCustomerHandler_CustomerWallets(t *testing.T) {
+	customer := createCustomer(t)
+	id := customer.ID.String()
+	require.Equal(t, domain.Tier(domain.TIER_ONE), customer.Tier)
+
+	response := serveCustomer(t, "POST", "/v1/customers", common.CreateCustomerRequest{Name: "Ada Obi", Email: customer.Email})
+	require.Equal(t, http.StatusConflict, response.Code)
+
+	createWalletWith(t, common.CreateWalletRequest{CustomerID: id, Status: "active"})
+	createWalletWith(t, common.CreateWalletRequest{CustomerID: id, Status: "active", Currency: "USD"})
+
+	r := SetupRouter()
+	r.POST("/v1/wallet", handler.CreateWallet)
+	createWalletFor := func(body common.CreateWalletRequest) int {
+		jsonValue, _ := json.Marshal(body)
+		request, err := http.NewRequest("POST", "/v1/wallet", bytes.NewBuffer(jsonValue))
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response.Code
+	}
+	require.Equal(t, http.StatusBadRequest, createWalletFor(common.CreateWalletRequest{Status: "active"}))
+	require.Equal(t, http.StatusNotFound, createWalletFor(common.CreateWalletRequest{CustomerID: uuid.NewV4().String(), Status: "active"}))
+
+	tier := "tier2"
+	response = serveCustomer(t, "PATCH", "/v1/customers/"+id, common.UpdateCustomerRequest{Tier: &tier})
+	require.Equal(t, http.StatusOK, response.Code)
+
+	response = serveCustomer(t, "GET", "/v1/customers/"+id+"/wallets", nil)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var wallets common.GetCustomerWalletsResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &wallets))
+	require.Len(t, wallets.Data, 2)
+	for _, wallet := range wallets.Data {
+		require.Equal(t, customer.ID, wallet.Owner)
+		require.Equal(t, domain.Tier(domain.TIER_TWO), wallet.Tier)
+	}
+
+	suspended := "suspended"
+	response = serveCustomer(t, "PATCH", "/v1/customers/"+id, common.UpdateCustomerRequest{Status: &suspended})
+	require.Equal(t, http.StatusOK, response.Code)
+	require.Equal(t, http.StatusUnprocessableEntity, createWalletFor(common.CreateWalletRequest{CustomerID: id, Status: "active"}))
+
+	require.Equal(t, http.StatusUnprocessableEntity, serveCustomer(t, "DELETE", "/v1/customers/"+id, nil).Code)
+
+	empty := createCustomer(t)
+	require.Equal(t, http.StatusNoContent, serveCustomer(t, "DELETE", "/v1/customers/"+empty.ID.String(), nil).Code)
+	require.Equal(t, http.StatusNotFound, serveCustomer(t, "GET", "/v1/customers/"+empty.ID.String(), nil).Code)
+}
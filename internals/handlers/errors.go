@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/middleware"
+)
+
+// forbidden is the message a principal gets when acting on a resource they do not hold
+const forbidden = "access to this resource is forbidden"
+
+// errorStatus maps a service error onto an http status code, defaulting to fallback
+func errorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrSupervisorRequired),
+		errors.Is(err, domain.ErrSelfApproval):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrAlreadyReversed),
+		errors.Is(err, domain.ErrCustomerExists):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrInvalidAccountNumber),
+		errors.Is(err, domain.ErrInvalidTransactionType),
+		errors.Is(err, domain.ErrInvalidAmount),
+		errors.Is(err, domain.ErrInvalidRange),
+		errors.Is(err, domain.ErrUnsupportedCurrency),
+		errors.Is(err, domain.ErrInvalidStatus),
+		errors.Is(err, domain.ErrInvalidFeeSchedule),
+		errors.Is(err, domain.ErrInvalidSchedule),
+		errors.Is(err, domain.ErrInvalidProduct):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrInsufficientBalance),
+		errors.Is(err, domain.ErrSameWallet),
+		errors.Is(err, domain.ErrCurrencyMismatch),
+		errors.Is(err, domain.ErrRateUnavailable),
+		errors.Is(err, domain.ErrConversionTooSmall),
+		errors.Is(err, domain.ErrQuoteRequired),
+		errors.Is(err, domain.ErrQuoteExpired),
+		errors.Is(err, domain.ErrQuoteUsed),
+		errors.Is(err, domain.ErrQuoteMismatch),
+		errors.Is(err, domain.ErrHoldNotActive),
+		errors.Is(err, domain.ErrHoldExpired),
+		errors.Is(err, domain.ErrCaptureExceedsHold),
+		errors.Is(err, domain.ErrApprovalNotPending),
+		errors.Is(err, domain.ErrApprovalExpired),
+		errors.Is(err, domain.ErrLimitExceeded),
+		errors.Is(err, domain.ErrStatusTransition),
+		errors.Is(err, domain.ErrDebitNotAllowed),
+		errors.Is(err, domain.ErrCreditNotAllowed),
+		errors.Is(err, domain.ErrWalletNotEmpty),
+		errors.Is(err, domain.ErrImmutableTransaction),
+		errors.Is(err, domain.ErrScheduleNotActive),
+		errors.Is(err, domain.ErrCustomerNotActive),
+		errors.Is(err, domain.ErrCustomerHasWallets),
+		errors.Is(err, domain.ErrAPIKeyRevoked),
+		errors.Is(err, domain.ErrIdempotencyConflict),
+		errors.Is(err, domain.ErrReverseReversal):
+		return http.StatusUnprocessableEntity
+	default:
+		return fallback
+	}
+}
+
+// actor names the principal a request acts for, empty when it was not authenticated
+func actor(c *gin.Context) string {
+	if principal := middleware.GetPrincipal(c); principal != nil {
+		return principal.Subject
+	}
+	return ""
+}
+
+// pendingApproval answers 202 with the approval request when err says the debit was queued for a
+// checker, reporting whether it did
+func pendingApproval(c *gin.Context, err error, handlerName string) bool {
+	var pending *domain.PendingApprovalError
+	if !errors.As(err, &pending) {
+		return false
+	}
+
+	c.JSON(http.StatusAccepted, result.ReturnSuccessResult(pending.Approval, message.GetResponseMessage(handlerName, types.PENDING_APPROVAL)))
+	return true
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/ports"
+)
+
+type feeHandler struct {
+	FeeService  ports.IFeeService
+	logger      *log.Logger
+	handlerName string
+}
+
+// NewFeeHandler function creates a new instance for fee schedule handler
+func NewFeeHandler(fs ports.IFeeService, l *log.Logger, n string) ports.IFeeHandler {
+	return &feeHandler{
+		FeeService:  fs,
+		logger:      l,
+		handlerName: n,
+	}
+}
+
+// GetFeeSchedules godoc
+// @Summary      List fee schedules
+// @Description  get every fee schedule with its bands
+// @Tags         fee
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  common.GetFeeSchedulesResponse
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /fees [get]
+func (fh *feeHandler) GetFeeSchedules(c *gin.Context) {
+	schedules, err := fh.FeeService.GetFeeSchedules()
+	if err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(schedules, message.GetResponseMessage(fh.handlerName, types.OKAY)))
+}
+
+// SaveFeeSchedule godoc
+// @Summary      Create or replace a fee schedule
+// @Description  price the fee on a purpose for a wallet tier and currency, replacing any schedule already set for them
+// @Tags         fee
+// @Accept       json
+// @Produce      json
+// @Param        schedule  body      common.SaveFeeScheduleRequest  true  "Fee schedule"
+// @Success      200  {object}  common.GetFeeScheduleResponse
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /fees [post]
+func (fh *feeHandler) SaveFeeSchedule(c *gin.Context) {
+	var body common.SaveFeeScheduleRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	schedule, err := fh.FeeService.SaveFeeSchedule(body)
+	if err != nil {
+		fh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(schedule, message.GetResponseMessage(fh.handlerName, types.UPDATED)))
+}
+
+// DeleteFeeSchedule godoc
+// @Summary      Delete a fee schedule
+// @Description  stop charging the fee a schedule sets
+// @Tags         fee
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Fee schedule ID"
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /fees/{id} [delete]
+func (fh *feeHandler) DeleteFeeSchedule(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	err := fh.FeeService.DeleteFeeSchedule(params.ID)
+	if err != nil {
+		fh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusNoContent, result.ReturnSuccessMessage(types.DELETED))
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/services"
+	"wallet_engine/internals/repositories"
+)
+
+func saveFeeSchedule(t *testing.T, body common.SaveFeeScheduleRequest) *httptest.ResponseRecorder {
+	r := SetupRouter()
+	feeHandler := NewFeeHandler(services.NewFeeService(*feeRepository, *repositories.NewRepository[domain.FeeBand](DBConnection), logging, DBConnection), logging, "Fee schedule")
+	r.POST("/v1/fees", feeHandler.SaveFeeSchedule)
+
+	jsonValue, _ := json.Marshal(body)
+	request, err := http.NewRequest("POST", "/v1/fees", bytes.NewBuffer(jsonValue))
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	return response
+}
+
+func TestFeeHandler_ChargeFees(t *testing.T) {
+	response := saveFeeSchedule(t, common.SaveFeeScheduleRequest{
+		Purpose:  "withdrawal",
+		Currency: "KES",
+		Type:     "tiered",
+		MaxFee:   500,
+		Bands: []common.FeeBandRequest{
+			{UpTo: 1000, FlatAmount: 10},
+			{RateBps: 100},
+		},
+	})
+	require.Equal(t, http.StatusOK, response.Code)
+
+	response = saveFeeSchedule(t, common.SaveFeeScheduleRequest{
+		Purpose:  "transfer",
+		Currency: "KES",
+		Type:     "percentage",
+		RateBps:  100,
+		MinFee:   20,
+	})
+	require.Equal(t, http.StatusOK, response.Code)
+
+	response = saveFeeSchedule(t, common.SaveFeeScheduleRequest{
+		Purpose: "withdrawal",
+		Type:    "tiered",
+		Bands:   []common.FeeBandRequest{{RateBps: 10}, {UpTo: 100}},
+	})
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	wallet := createWalletWith(t, common.CreateWalletRequest{Status: "active", Currency: "KES"})
+	other := createWalletWith(t, common.CreateWalletRequest{Status: "active", Currency: "KES"})
+	id := wallet.Data.ID.String()
+	creditWallet(t, id, 100000)
+
+	withdraw := func(amount int64) domain.Transaction {
+		response := transactWithKey(t, id, uuid.NewV4().String(), common.CreateTransactionRequest{
+			TransactionType: "debit",
+			Purpose:         "withdrawal",
+			Amount:          amount,
+			AccountID:       id,
+		})
+		require.Equal(t, http.StatusOK, response.Code)
+
+		var body common.CreateTransactionResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		return body.Data
+	}
+
+	small := withdraw(500)
+	require.Len(t, small.Fees, 1)
+	require.Equal(t, int64(10), small.Fees[0].Amount)
+	require.Equal(t, domain.PurposeType(domain.FEE), small.Fees[0].Purpose)
+	require.Equal(t, small.ID, *small.Fees[0].ParentID)
+
+	large := withdraw(80000)
+	require.Equal(t, int64(500), large.Fees[0].Amount)
+
+	response = transfer(t, common.CreateTransferRequest{
+		SourceWalletID:      id,
+		DestinationWalletID: other.Data.ID.String(),
+		Amount:              1000,
+	})
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var moved common.CreateTransferResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &moved))
+	require.Equal(t, int64(20), moved.Data.Debit.Fees[0].Amount)
+	require.Equal(t, int64(100000-500-10-80000-500-1000-20), moved.Data.Debit.Fees[0].BalanceAfter)
+}
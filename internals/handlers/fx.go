@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/ports"
+)
+
+type fxHandler struct {
+	FXService   ports.IFXService
+	logger      *log.Logger
+	handlerName string
+}
+
+// NewFXHandler function creates a new instance for foreign exchange handler
+func NewFXHandler(fs ports.IFXService, l *log.Logger, n string) ports.IFXHandler {
+	return &fxHandler{
+		FXService:   fs,
+		logger:      l,
+		handlerName: n,
+	}
+}
+
+// CreateQuote godoc
+// @Summary      Quote a currency conversion
+// @Description  lock an exchange rate for a short time to settle a cross currency transfer with
+// @Tags         fx
+// @Accept       json
+// @Produce      json
+// @Param quote body common.CreateQuoteRequest true "Create quote"
+// @Success      201  {object}  common.CreateQuoteResponse
+// @Failure      400  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /fx/quotes [post]
+func (fh *fxHandler) CreateQuote(c *gin.Context) {
+	var body common.CreateQuoteRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		fh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	quote, err := fh.FXService.CreateQuote(body)
+	if err != nil {
+		fh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(quote, message.GetResponseMessage(fh.handlerName, types.CREATED)))
+}
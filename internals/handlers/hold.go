@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/ports"
+)
+
+type holdHandler struct {
+	HoldService ports.IHoldService
+	logger      *log.Logger
+	handlerName string
+}
+
+// NewHoldHandler function creates a new instance for hold handler
+func NewHoldHandler(hs ports.IHoldService, l *log.Logger, n string) ports.IHoldHandler {
+	return &holdHandler{
+		HoldService: hs,
+		logger:      l,
+		handlerName: n,
+	}
+}
+
+// GetHoldByID godoc
+// @Summary      Get a hold
+// @Description  get hold by ID
+// @Tags         hold
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Hold ID"
+// @Success      200  {object}  common.GetHoldResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /holds/{id} [get]
+func (hh *holdHandler) GetHoldByID(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		hh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	hold, err := hh.HoldService.GetHoldByID(params.ID)
+	if err != nil {
+		hh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(hold, message.GetResponseMessage(hh.handlerName, types.OKAY)))
+}
+
+// CreateHold godoc
+// @Summary      Place a hold on a wallet
+// @Description  reserve funds, reducing the available balance but not the ledger balance. A hold above APPROVAL_THRESHOLD is not placed until a second person approves it
+// @Tags         hold
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Wallet ID"
+// @Param hold body common.CreateHoldRequest true "Create hold"
+// @Success      201  {object}  common.GetHoldResponse
+// @Success      202  {object}  common.GetApprovalResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{id}/holds [post]
+func (hh *holdHandler) CreateHold(c *gin.Context) {
+	var body common.CreateHoldRequest
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		hh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		hh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	hold, err := hh.HoldService.CreateHold(params, body, actor(c))
+	if pendingApproval(c, err, hh.handlerName) {
+		return
+	}
+
+	if err != nil {
+		hh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusBadRequest), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(hold, message.GetResponseMessage(hh.handlerName, types.CREATED)))
+}
+
+// CaptureHold godoc
+// @Summary      Capture a hold
+// @Description  settle a hold fully or partially into a debit, releasing whatever is not captured
+// @Tags         hold
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Hold ID"
+// @Param capture body common.CaptureHoldRequest false "Capture hold"
+// @Success      200  {object}  common.CaptureHoldResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /holds/{id}/capture [post]
+func (hh *holdHandler) CaptureHold(c *gin.Context) {
+	var body common.CaptureHoldRequest
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		hh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			hh.logger.Error(err)
+			c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+			return
+		}
+	}
+
+	hold, transaction, err := hh.HoldService.CaptureHold(params, body)
+	if err != nil {
+		hh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusBadRequest), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	captured := common.CaptureHoldResult{
+		Hold:        *hold,
+		Transaction: *transaction,
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(captured, message.GetResponseMessage(hh.handlerName, types.CAPTURED)))
+}
+
+// ReleaseHold godoc
+// @Summary      Release a hold
+// @Description  cancel a hold and give its funds back to the available balance
+// @Tags         hold
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Hold ID"
+// @Success      200  {object}  common.GetHoldResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /holds/{id}/release [post]
+func (hh *holdHandler) ReleaseHold(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		hh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	hold, err := hh.HoldService.ReleaseHold(params)
+	if err != nil {
+		hh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusBadRequest), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(hold, message.GetResponseMessage(hh.handlerName, types.RELEASED)))
+}
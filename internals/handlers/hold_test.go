@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/pkg/config"
+)
+
+func placeHold(t *testing.T, id string, body common.CreateHoldRequest) *httptest.ResponseRecorder {
+	r := SetupRouter()
+	r.POST("/v1/wallet/:id/holds", holdsHandler.CreateHold)
+
+	jsonValue, _ := json.Marshal(body)
+	request, err := http.NewRequest("POST", fmt.Sprintf("/v1/wallet/%v/holds", id), bytes.NewBuffer(jsonValue))
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestHoldHandler_CaptureHold(t *testing.T) {
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	creditWallet(t, id, 1000)
+
+	response := placeHold(t, id, common.CreateHoldRequest{Amount: 600})
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var hold common.GetHoldResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &hold))
+
+	stored, err := walletService.GetWalletByID(id)
+	require.NoError(t, err)
+	require.Equal(t, int64(1000), stored.Balance)
+	require.Equal(t, int64(400), stored.AvailableBalance)
+
+	// the reserved funds can no longer be spent elsewhere
+	require.Equal(t, http.StatusUnprocessableEntity, placeHold(t, id, common.CreateHoldRequest{Amount: 500}).Code)
+
+	r := SetupRouter()
+	r.POST("/v1/holds/:id/capture", holdsHandler.CaptureHold)
+
+	jsonValue, _ := json.Marshal(common.CaptureHoldRequest{Amount: 200})
+	request, err := http.NewRequest("POST", fmt.Sprintf("/v1/holds/%v/capture", hold.Data.ID), bytes.NewBuffer(jsonValue))
+	require.NoError(t, err)
+
+	capture := httptest.NewRecorder()
+
+	r.ServeHTTP(capture, request)
+
+	require.Equal(t, http.StatusOK, capture.Code)
+
+	var captured common.CaptureHoldResponse
+	require.NoError(t, json.Unmarshal(capture.Body.Bytes(), &captured))
+	require.Equal(t, domain.HoldStatus(domain.HOLD_CAPTURED), captured.Data.Hold.Status)
+	require.Equal(t, int64(200), captured.Data.Transaction.Amount)
+
+	stored, err = walletService.GetWalletByID(id)
+	require.NoError(t, err)
+	require.Equal(t, int64(800), stored.Balance)
+	require.Equal(t, int64(0), stored.HeldBalance)
+	require.Equal(t, int64(800), stored.AvailableBalance)
+}
+
+func TestHoldHandler_ReleaseHold(t *testing.T) {
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	creditWallet(t, id, 300)
+
+	response := placeHold(t, id, common.CreateHoldRequest{Amount: 300})
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var hold common.GetHoldResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &hold))
+
+	r := SetupRouter()
+	r.POST("/v1/holds/:id/release", holdsHandler.ReleaseHold)
+
+	release := func() int {
+		request, err := http.NewRequest("POST", fmt.Sprintf("/v1/holds/%v/release", hold.Data.ID), nil)
+		require.NoError(t, err)
+		recorder := httptest.NewRecorder()
+		r.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	require.Equal(t, http.StatusOK, release())
+	require.Equal(t, http.StatusUnprocessableEntity, release())
+
+	stored, err := walletService.GetWalletByID(id)
+	require.NoError(t, err)
+	require.Equal(t, int64(300), stored.AvailableBalance)
+}
+
+func TestHoldHandler_CreateHoldLimits(t *testing.T) {
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	creditWallet(t, id, 4000000)
+	creditWallet(t, id, 4000000)
+
+	response := placeHold(t, id, common.CreateHoldRequest{Amount: 6000000})
+	require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	require.Contains(t, response.Body.String(), "max single transaction")
+
+	stored, err := walletService.GetWalletByID(id)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), stored.HeldBalance)
+}
+
+func TestHoldHandler_CaptureHoldLimits(t *testing.T) {
+	threshold := "5000000"
+	previous := config.Instance
+	config.Instance = &config.Config{ApprovalThreshold: &threshold}
+	t.Cleanup(func() { config.Instance = previous })
+
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	creditWallet(t, id, 4000000)
+	creditWallet(t, id, 4000000)
+
+	response := placeHold(t, id, common.CreateHoldRequest{Amount: 3000000})
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var hold common.GetHoldResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &hold))
+
+	require.Equal(t, http.StatusOK, transactWithKey(t, id, "", common.CreateTransactionRequest{
+		TransactionType: "debit",
+		Purpose:         "withdrawal",
+		Amount:          3000000,
+		AccountID:       id,
+	}).Code)
+
+	r := SetupRouter()
+	r.POST("/v1/holds/:id/capture", holdsHandler.CaptureHold)
+
+	request, err := http.NewRequest("POST", fmt.Sprintf("/v1/holds/%v/capture", hold.Data.ID), bytes.NewBufferString("{}"))
+	require.NoError(t, err)
+
+	capture := httptest.NewRecorder()
+	r.ServeHTTP(capture, request)
+
+	require.Equal(t, http.StatusUnprocessableEntity, capture.Code)
+	require.Contains(t, capture.Body.String(), "daily debit total")
+
+	stored, err := walletService.GetWalletByID(id)
+	require.NoError(t, err)
+	require.Equal(t, int64(3000000), stored.HeldBalance)
+}
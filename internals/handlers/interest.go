@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/ports"
+)
+
+type interestHandler struct {
+	InterestService ports.IInterestService
+	logger          *log.Logger
+	handlerName     string
+}
+
+// NewInterestHandler function creates a new instance for interest handler
+func NewInterestHandler(is ports.IInterestService, l *log.Logger, n string) ports.IInterestHandler {
+	return &interestHandler{
+		InterestService: is,
+		logger:          l,
+		handlerName:     n,
+	}
+}
+
+// GetAccruals godoc
+// @Summary      List a savings wallet's interest accruals
+// @Description  the interest earned on each day's closing balance, newest first, with the payout that settled it
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Wallet ID"
+// @Success      200  {object}  common.GetInterestAccrualsResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{id}/interest [get]
+func (ih *interestHandler) GetAccruals(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		ih.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	accruals, err := ih.InterestService.GetAccruals(params)
+	if err != nil {
+		ih.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(accruals, message.GetResponseMessage(ih.handlerName, types.OKAY)))
+}
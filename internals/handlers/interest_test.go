@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/services"
+	"wallet_engine/internals/repositories"
+	"wallet_engine/pkg/config"
+)
+
+func TestDailyInterest_BankersRounding(t *testing.T) {
+	// 3650 bps a year is exactly 1 in 1000 a day
+	require.Equal(t, int64(0), domain.DailyInterest(500, 3650))
+	require.Equal(t, int64(2), domain.DailyInterest(1500, 3650))
+	require.Equal(t, int64(2), domain.DailyInterest(2500, 3650))
+	require.Equal(t, int64(3), domain.DailyInterest(2501, 3650))
+	require.Equal(t, int64(0), domain.DailyInterest(-1000000, 3650))
+}
+
+func TestInterestHandler_AccrueInterest(t *testing.T) {
+	interestService := services.NewInterestService(*walletRepository, *transactionRepository, *repositories.NewRepository[domain.InterestAccrual](DBConnection), walletService, domain.DAILY_PAYOUT, logging, DBConnection)
+
+	previous := config.Instance
+	t.Cleanup(func() { config.Instance = previous })
+
+	// without a configured rate the savings product is not offered
+	config.Instance = &config.Config{}
+	invalid := &domain.Wallet{Status: domain.ACTIVE, ProductType: domain.SAVINGS_PRODUCT}
+	require.ErrorIs(t, walletService.CreateWallet(invalid), domain.ErrInvalidProduct)
+
+	rate := "3650"
+	config.Instance = &config.Config{SavingsInterestRateBps: &rate}
+	savings := createWalletWith(t, common.CreateWalletRequest{Status: "active", ProductType: "savings"})
+	require.Equal(t, int64(3650), savings.Data.AnnualInterestRateBps)
+	id := savings.Data.ID.String()
+	creditWallet(t, id, 1000000)
+
+	// accrue today and tomorrow, each paid out at the close of its day
+	accrued, err := interestService.AccrueInterest(time.Now().Add(48 * time.Hour))
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, accrued, 2)
+
+	// days already accrued are never accrued twice
+	_, err = interestService.AccrueInterest(time.Now().Add(48 * time.Hour))
+	require.NoError(t, err)
+
+	wallet, err := walletService.GetWalletByID(id)
+	require.NoError(t, err)
+	require.Equal(t, int64(1002001), wallet.Balance)
+	require.Equal(t, int64(0), wallet.AccruedInterest)
+
+	r := SetupRouter()
+	r.GET("/v1/wallet/:id/interest", NewInterestHandler(interestService, logging, "Interest").GetAccruals)
+
+	request, err := http.NewRequest("GET", "/v1/wallet/"+id+"/interest", nil)
+	require.NoError(t, err)
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var body common.GetInterestAccrualsResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	require.Len(t, body.Data, 2)
+	require.Equal(t, int64(1001), body.Data[0].Amount)
+	require.Equal(t, int64(1000), body.Data[1].Amount)
+	require.NotNil(t, body.Data[0].PaidBy)
+
+	var payout domain.Transaction
+	require.NoError(t, DBConnection.First(&payout, "id = ?", body.Data[0].PaidBy).Error)
+	require.Equal(t, domain.PurposeType(domain.INTEREST), payout.Purpose)
+}
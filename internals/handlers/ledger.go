@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/ports"
+)
+
+type ledgerHandler struct {
+	LedgerService ports.ILedgerService
+	logger        *log.Logger
+	handlerName   string
+}
+
+// NewLedgerHandler function creates a new instance for general ledger handler
+func NewLedgerHandler(ls ports.ILedgerService, l *log.Logger, n string) ports.ILedgerHandler {
+	return &ledgerHandler{
+		LedgerService: ls,
+		logger:        l,
+		handlerName:   n,
+	}
+}
+
+// GetAccounts godoc
+// @Summary      List ledger accounts
+// @Description  the chart of accounts, internal accounts and wallet liability accounts alike
+// @Tags         ledger
+// @Accept       json
+// @Produce      json
+// @Param        type      query  string  false  "asset, liability, revenue or expense"
+// @Param        currency  query  string  false  "ISO 4217 currency code"
+// @Success      200  {object}  common.GetLedgerAccountsResponse
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /ledger/accounts [get]
+func (lh *ledgerHandler) GetAccounts(c *gin.Context) {
+	var filter common.LedgerAccountFilterRequest
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		lh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	accounts, err := lh.LedgerService.GetAccounts(filter)
+	if err != nil {
+		lh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(accounts, message.GetResponseMessage(lh.handlerName, types.OKAY)))
+}
+
+// GetTrialBalance godoc
+// @Summary      Get the trial balance
+// @Description  every ledger account's debit or credit balance with per currency totals proving the books balance
+// @Tags         ledger
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  common.GetTrialBalanceResponse
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /ledger/trial-balance [get]
+func (lh *ledgerHandler) GetTrialBalance(c *gin.Context) {
+	trialBalance, err := lh.LedgerService.GetTrialBalance()
+	if err != nil {
+		lh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(trialBalance, message.GetResponseMessage(lh.handlerName, types.OKAY)))
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/repositories"
+)
+
+func TestLedgerHandler_GetTrialBalance(t *testing.T) {
+	r := SetupRouter()
+	r.GET("/v1/ledger/trial-balance", NewLedgerHandler(ledgerService, logging, "Ledger").GetTrialBalance)
+
+	source := createWallet(t)
+	destination := createWallet(t)
+	creditWallet(t, source.Data.ID.String(), 1000)
+	response := transfer(t, common.CreateTransferRequest{
+		SourceWalletID:      source.Data.ID.String(),
+		DestinationWalletID: destination.Data.ID.String(),
+		Amount:              400,
+	})
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	request, err := http.NewRequest("GET", "/v1/ledger/trial-balance", nil)
+	require.NoError(t, err)
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var body common.GetTrialBalanceResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+	require.True(t, body.Data.Balanced)
+
+	credits := map[string]int64{}
+	for _, line := range body.Data.Lines {
+		credits[line.Code] = line.Credit
+	}
+	require.Equal(t, int64(600), credits[fmt.Sprintf("%v-%d", domain.WALLET_ACCOUNT, source.Data.AccountID)])
+	require.Equal(t, int64(400), credits[fmt.Sprintf("%v-%d", domain.WALLET_ACCOUNT, destination.Data.AccountID)])
+
+	unbalanced := &domain.JournalEntry{Postings: []domain.Posting{{Amount: 100, Currency: domain.DefaultCurrency}}}
+	require.ErrorIs(t, ledgerService.Post(DBConnection, unbalanced), domain.ErrUnbalancedEntry)
+}
+
+func TestDatabase_PostOpeningBalances(t *testing.T) {
+	wallet := createWallet(t).Data
+
+	// a wallet funded before the ledger existed holds a balance with nothing posted for it
+	require.NoError(t, DBConnection.Model(&domain.Wallet{}).Where("id = ?", wallet.ID).Update("balance", 2500).Error)
+
+	require.NoError(t, db.MigrateAll(DBConnection))
+	require.NoError(t, db.MigrateAll(DBConnection))
+
+	account, err := repositories.NewRepository[domain.LedgerAccount](DBConnection).GetBy("wallet_id = ?", wallet.ID)
+	require.NoError(t, err)
+
+	var postings []domain.Posting
+	require.NoError(t, DBConnection.Where("ledger_account_id = ?", account.ID).Find(&postings).Error)
+	require.Len(t, postings, 1)
+	require.Equal(t, int64(-2500), postings[0].Amount)
+
+	trialBalance, err := ledgerService.GetTrialBalance()
+	require.NoError(t, err)
+	require.True(t, trialBalance.Balanced)
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/ports"
+)
+
+type limitHandler struct {
+	LimitService ports.ILimitService
+	logger       *log.Logger
+	handlerName  string
+}
+
+// NewLimitHandler function creates a new instance for tier limit handler
+func NewLimitHandler(ls ports.ILimitService, l *log.Logger, n string) ports.ILimitHandler {
+	return &limitHandler{
+		LimitService: ls,
+		logger:       l,
+		handlerName:  n,
+	}
+}
+
+// GetLimits godoc
+// @Summary      List tier limits
+// @Description  get the transaction limits of every wallet tier
+// @Tags         limit
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  common.GetTierLimitsResponse
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /limits [get]
+func (lh *limitHandler) GetLimits(c *gin.Context) {
+	limits, err := lh.LimitService.GetLimits()
+	if err != nil {
+		lh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(limits, message.GetResponseMessage(lh.handlerName, types.OKAY)))
+}
+
+// UpdateLimit godoc
+// @Summary      Update a tier's limits
+// @Description  change the transaction limits of a wallet tier, creating the tier if it is new
+// @Tags         limit
+// @Accept       json
+// @Produce      json
+// @Param        tier   path      string  true  "Tier"
+// @Param limit body common.UpdateTierLimitRequest true "Update limit"
+// @Success      200  {object}  common.GetTierLimitResponse
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /limits/{tier} [put]
+func (lh *limitHandler) UpdateLimit(c *gin.Context) {
+	var body common.UpdateTierLimitRequest
+	var params common.GetByTierRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		lh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&body); err != nil {
+		lh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	limit, err := lh.LimitService.UpdateLimit(params, body)
+	if err != nil {
+		lh.logger.Error(err)
+		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(limit, message.GetResponseMessage(lh.handlerName, types.UPDATED)))
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/ports"
+)
+
+type reconciliationHandler struct {
+	ReconciliationService ports.IReconciliationService
+	logger                *log.Logger
+	handlerName           string
+}
+
+// NewReconciliationHandler function creates a new instance for reconciliation handler
+func NewReconciliationHandler(rs ports.IReconciliationService, l *log.Logger, n string) ports.IReconciliationHandler {
+	return &reconciliationHandler{
+		ReconciliationService: rs,
+		logger:                l,
+		handlerName:           n,
+	}
+}
+
+// VerifyChain godoc
+// @Summary      Verify a wallet's hash chain
+// @Description  walk a wallet's transactions oldest first and report the first one whose hash no longer matches
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Wallet ID"
+// @Success      200  {object}  common.GetChainVerificationResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{id}/verify [get]
+func (rh *reconciliationHandler) VerifyChain(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		rh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	verification, err := rh.ReconciliationService.VerifyChain(params)
+	if err != nil {
+		rh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(verification, message.GetResponseMessage(rh.handlerName, types.OKAY)))
+}
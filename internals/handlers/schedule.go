@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/common/types"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/middleware"
+)
+
+type scheduleHandler struct {
+	ScheduleService ports.IScheduleService
+	WalletService   ports.IWalletService
+	logger          *log.Logger
+	handlerName     string
+}
+
+// NewScheduleHandler function creates a new instance for scheduled transaction handler, ws
+// resolves who holds the wallet a schedule pays from
+func NewScheduleHandler(ss ports.IScheduleService, ws ports.IWalletService, l *log.Logger, n string) ports.IScheduleHandler {
+	return &scheduleHandler{
+		ScheduleService: ss,
+		WalletService:   ws,
+		logger:          l,
+		handlerName:     n,
+	}
+}
+
+// CreateSchedule godoc
+// @Summary      Create a scheduled transaction
+// @Description  set up a standing order that runs on a cron expression or a fixed interval
+// @Tags         schedule
+// @Accept       json
+// @Produce      json
+// @Param        schedule  body      common.CreateScheduleRequest  true  "Scheduled transaction"
+// @Success      201  {object}  common.GetScheduleResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /schedules [post]
+func (sh *scheduleHandler) CreateSchedule(c *gin.Context) {
+	var body common.CreateScheduleRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		sh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if !middleware.Permits(c, body.WalletID, middleware.WalletOwner(sh.WalletService)) {
+		c.JSON(http.StatusForbidden, result.ReturnErrorResult(forbidden))
+		return
+	}
+
+	// a standing credit with no wallet to draw on brings funds in, like any other credit
+	if body.DestinationWalletID == "" && domain.TxnType(body.TransactionType) == domain.CREDIT && !middleware.MayCredit(c) {
+		c.JSON(http.StatusForbidden, result.ReturnErrorResult(forbidden))
+		return
+	}
+
+	schedule, err := sh.ScheduleService.CreateSchedule(body)
+	if err != nil {
+		sh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusBadRequest), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(schedule, message.GetResponseMessage(sh.handlerName, types.CREATED)))
+}
+
+// GetSchedule godoc
+// @Summary      Get a scheduled transaction
+// @Description  get a scheduled transaction with the history of its runs, failures included
+// @Tags         schedule
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Schedule ID"
+// @Success      200  {object}  common.GetScheduleResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /schedules/{id} [get]
+func (sh *scheduleHandler) GetSchedule(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		sh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	schedule, err := sh.ScheduleService.GetSchedule(params)
+	if err != nil {
+		sh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(schedule, message.GetResponseMessage(sh.handlerName, types.OKAY)))
+}
+
+// GetWalletSchedules godoc
+// @Summary      List a wallet's scheduled transactions
+// @Description  every scheduled transaction paid from or applied to a wallet, newest first
+// @Tags         schedule
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Wallet ID"
+// @Success      200  {object}  common.GetSchedulesResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{id}/schedules [get]
+func (sh *scheduleHandler) GetWalletSchedules(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		sh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	schedules, err := sh.ScheduleService.GetWalletSchedules(params)
+	if err != nil {
+		sh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(schedules, message.GetResponseMessage(sh.handlerName, types.OKAY)))
+}
+
+// CancelSchedule godoc
+// @Summary      Cancel a scheduled transaction
+// @Description  stop a scheduled transaction from running again
+// @Tags         schedule
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Schedule ID"
+// @Success      200  {object}  common.GetScheduleResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /schedules/{id}/cancel [post]
+func (sh *scheduleHandler) CancelSchedule(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		sh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	schedule, err := sh.ScheduleService.CancelSchedule(params)
+	if err != nil {
+		sh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(schedule, message.GetResponseMessage(sh.handlerName, types.CANCELLED)))
+}
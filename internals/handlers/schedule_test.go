@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"wallet_engine/internals/common"
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/services"
+	"wallet_engine/internals/repositories"
+)
+
+func TestScheduleHandler_RunDueSchedules(t *testing.T) {
+	scheduleService := services.NewScheduleService(*repositories.NewRepository[domain.ScheduledTransaction](DBConnection), *repositories.NewRepository[domain.ScheduledExecution](DBConnection), walletService, logging)
+	scheduleHandler := NewScheduleHandler(scheduleService, walletService, logging, "Schedule")
+
+	r := SetupRouter()
+	r.POST("/v1/schedules", scheduleHandler.CreateSchedule)
+	r.GET("/v1/schedules/:id", scheduleHandler.GetSchedule)
+	r.POST("/v1/schedules/:id/cancel", scheduleHandler.CancelSchedule)
+
+	serve := func(method, url string, body interface{}) *httptest.ResponseRecorder {
+		jsonValue, _ := json.Marshal(body)
+		request, err := http.NewRequest(method, url, bytes.NewBuffer(jsonValue))
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+
+	schedule := func(body common.CreateScheduleRequest) domain.ScheduledTransaction {
+		response := serve("POST", "/v1/schedules", body)
+		require.Equal(t, http.StatusCreated, response.Code, response.Body.String())
+
+		var created common.GetScheduleResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &created))
+		return created.Data
+	}
+
+	source := createWallet(t).Data.ID.String()
+	destination := createWallet(t).Data.ID.String()
+	empty := createWallet(t).Data.ID.String()
+	creditWallet(t, source, 10000)
+
+	start := time.Now().Add(-10 * time.Minute)
+	standingOrder := schedule(common.CreateScheduleRequest{
+		WalletID:            source,
+		DestinationWalletID: destination,
+		Amount:              5000,
+		IntervalSeconds:     3600,
+		StartAt:             &start,
+	})
+	overdrawn := schedule(common.CreateScheduleRequest{
+		WalletID:        empty,
+		TransactionType: "debit",
+		Purpose:         "withdrawal",
+		Amount:          5000,
+		IntervalSeconds: 3600,
+		StartAt:         &start,
+	})
+
+	response := serve("POST", "/v1/schedules", common.CreateScheduleRequest{
+		WalletID:        source,
+		TransactionType: "debit",
+		Purpose:         "withdrawal",
+		Amount:          5000,
+		Cron:            "0 9 * * 8",
+	})
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	_, err := scheduleService.RunDueSchedules()
+	require.NoError(t, err)
+
+	// nothing is due again until the next interval
+	_, err = scheduleService.RunDueSchedules()
+	require.NoError(t, err)
+
+	get := func(id string) domain.ScheduledTransaction {
+		response := serve("GET", "/v1/schedules/"+id, nil)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		var body common.GetScheduleResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		return body.Data
+	}
+
+	ran := get(standingOrder.ID.String())
+	require.Len(t, ran.Executions, 1)
+	require.Equal(t, domain.EXECUTION_SUCCEEDED, ran.Executions[0].Status)
+	require.NotNil(t, ran.Executions[0].TransactionID)
+	require.True(t, ran.NextRunAt.After(time.Now()))
+
+	wallet, err := walletService.GetWalletByID(destination)
+	require.NoError(t, err)
+	require.Equal(t, int64(5000), wallet.Balance)
+
+	failed := get(overdrawn.ID.String())
+	require.Len(t, failed.Executions, 1)
+	require.Equal(t, domain.EXECUTION_FAILED, failed.Executions[0].Status)
+	require.True(t, strings.Contains(failed.Executions[0].Error, "insufficient"), failed.Executions[0].Error)
+
+	for _, id := range []string{standingOrder.ID.String(), overdrawn.ID.String()} {
+		require.Equal(t, http.StatusOK, serve("POST", "/v1/schedules/"+id+"/cancel", nil).Code)
+	}
+	require.Equal(t, http.StatusUnprocessableEntity, serve("POST", "/v1/schedules/"+overdrawn.ID.String()+"/cancel", nil).Code)
+}
@@ -13,6 +13,7 @@ import (
 	"wallet_engine/internals/common/types"
 	"wallet_engine/internals/core/domain"
 	"wallet_engine/internals/core/ports"
+	"wallet_engine/internals/middleware"
 	"wallet_engine/pkg/utils"
 )
 
@@ -43,10 +44,13 @@ func NewWalletHandler(cs ports.IWalletService, l *log.Logger, n string) ports.IW
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "Wallet ID"
+// @Param        include query  string  false "transactions to load the wallet's transactions"
 // @Success      200  {object}  common.GetWalletResponse
 // @Failure      400  {object}  common.Error
 // @Failure      404  {object}  common.Error
 // @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /wallet/{id} [get]
 func (wh *walletHandler) GetWalletByID(c *gin.Context) {
 	var params common.GetByIDRequest
@@ -56,7 +60,20 @@ func (wh *walletHandler) GetWalletByID(c *gin.Context) {
 		return
 	}
 
-	wallet, err := wh.WalletService.GetWalletByID(params.ID)
+	var query common.GetWalletQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	var wallet *domain.Wallet
+	var err error
+	if query.Include == "transactions" {
+		wallet, err = wh.WalletService.GetWalletWithTransactions(params.ID)
+	} else {
+		wallet, err = wh.WalletService.GetWalletByID(params.ID)
+	}
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			wh.logger.Error(err)
@@ -71,16 +88,53 @@ func (wh *walletHandler) GetWalletByID(c *gin.Context) {
 	c.JSON(http.StatusOK, result.ReturnSuccessResult(wallet, message.GetResponseMessage(wh.handlerName, types.OKAY)))
 }
 
+// GetWalletByAccountNumber godoc
+// @Summary      Get a wallet by account number
+// @Description  resolve a wallet from its 10 digit account number
+// @Tags         account
+// @Accept       json
+// @Produce      json
+// @Param        account_number   path      int  true  "Account number"
+// @Success      200  {object}  common.GetWalletResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /accounts/{account_number} [get]
+func (wh *walletHandler) GetWalletByAccountNumber(c *gin.Context) {
+	var params common.GetByAccountNumberRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	wallet, err := wh.WalletService.GetWalletByAccountNumber(params.AccountNumber)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(wallet, message.GetResponseMessage(wh.handlerName, types.OKAY)))
+}
+
 // CreateWallet godoc
 // @Summary      Create wallet
 // @Description  creates a wallet
 // @Tags         wallet
 // @Accept       json
 // @Produce      json
-// @Param wallet body common.CreateWalletRequest true "active or inactive"
+// @Param wallet body common.CreateWalletRequest true "pending or active"
 // @Success      200  {object}  common.GetWalletResponse
 // @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
 // @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /wallet [post]
 func (wh *walletHandler) CreateWallet(c *gin.Context) {
 	var body common.CreateWalletRequest
@@ -90,17 +144,34 @@ func (wh *walletHandler) CreateWallet(c *gin.Context) {
 		return
 	}
 
+	if !middleware.Permits(c, body.CustomerID, middleware.CustomerOwner) {
+		c.JSON(http.StatusForbidden, result.ReturnErrorResult(forbidden))
+		return
+	}
+
+	currency := domain.DefaultCurrency
+	if body.Currency != "" {
+		parsed, err := domain.ParseCurrency(body.Currency)
+		if err != nil {
+			wh.logger.Error(err)
+			c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+			return
+		}
+		currency = parsed
+	}
+
 	wallet := &domain.Wallet{
-		Owner:     uuid.NewV4(),
-		Status:    domain.State(body.Status),
-		Balance:   0,
-		AccountID: (&utils.Faker{}).RandomAccount(1000000000, 9999999999),
+		Owner:       uuid.FromStringOrNil(body.CustomerID),
+		Status:      domain.State(body.Status),
+		Balance:     0,
+		Currency:    currency,
+		ProductType: domain.ProductType(body.ProductType),
 	}
 
 	err := wh.WalletService.CreateWallet(wallet)
 	if err != nil {
 		wh.logger.Error(err)
-		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		c.JSON(errorStatus(err, http.StatusBadRequest), result.ReturnErrorResult(err.Error()))
 		return
 	}
 
@@ -116,7 +187,10 @@ func (wh *walletHandler) CreateWallet(c *gin.Context) {
 // @Param        id   path      string  true  "Wallet ID"
 // @Failure      400  {object}  common.Error
 // @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
 // @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /wallet/{id} [delete]
 func (wh walletHandler) DeleteWallet(c *gin.Context) {
 	var query common.GetByIDRequest
@@ -128,7 +202,7 @@ func (wh walletHandler) DeleteWallet(c *gin.Context) {
 	err := wh.WalletService.DeleteWallet(query.ID)
 	if err != nil {
 		wh.logger.Error(err)
-		c.JSON(http.StatusInternalServerError, result.ReturnErrorResult(err.Error()))
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
 		return
 	}
 	c.JSON(http.StatusNoContent, result.ReturnSuccessMessage(types.DELETED))
@@ -141,11 +215,15 @@ func (wh walletHandler) DeleteWallet(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "Wallet ID"
-// @Param        status query   string  true  "active or inactive"
+// @Param        status query   string  true  "pending, active, inactive, frozen, debit_blocked, credit_blocked or closed"
+// @Param        reason query   string  false "Why the status is changing"
+// @Param        actor  query   string  false "Who is changing the status"
 // @Success      200  {object}  common.GetWalletResponse
 // @Failure      400  {object}  common.Error
 // @Failure      404  {object}  common.Error
 // @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /wallet/{id}/activate [patch]
 func (wh *walletHandler) UpdateWallet(c *gin.Context) {
 	var query common.UpdateWalletRequest
@@ -165,24 +243,150 @@ func (wh *walletHandler) UpdateWallet(c *gin.Context) {
 	wallet, err := wh.WalletService.UpdateWallet(params, query)
 	if err != nil {
 		wh.logger.Error(err)
-		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		c.JSON(errorStatus(err, http.StatusBadRequest), result.ReturnErrorResult(err.Error()))
 		return
 	}
 	c.JSON(http.StatusOK, result.ReturnSuccessResult(wallet, message.GetResponseMessage(wh.handlerName, types.UPDATED)))
 }
 
+// CloseWallet godoc
+// @Summary      Close a wallet
+// @Description  close a wallet for good, sweeping any balance left to a destination wallet. A sweep above APPROVAL_THRESHOLD waits until a second person approves it
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                     true  "Wallet ID"
+// @Param        body   body      common.CloseWalletRequest  true  "Closure"
+// @Success      200  {object}  common.CloseWalletResponse
+// @Success      202  {object}  common.GetApprovalResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{id}/close [post]
+func (wh *walletHandler) CloseWallet(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	var body common.CloseWalletRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	// the closure is made by whoever is authenticated, so a maker cannot pass for someone else
+	if subject := actor(c); subject != "" {
+		body.Actor = subject
+	}
+
+	wallet, sweep, err := wh.WalletService.CloseWallet(params, body)
+	if pendingApproval(c, err, wh.handlerName) {
+		return
+	}
+
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusBadRequest), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	closed := common.ClosedWallet{
+		Wallet: *wallet,
+		Sweep:  sweep,
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(closed, message.GetResponseMessage(wh.handlerName, types.CLOSED)))
+}
+
+// DeriveBalance godoc
+// @Summary      Check a wallet's balance against its journal
+// @Description  recompute the balance from the wallet's transactions and report any drift from the stored balance
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Wallet ID"
+// @Success      200  {object}  common.GetLedgerBalanceResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{id}/balance-check [get]
+func (wh *walletHandler) DeriveBalance(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	balance, err := wh.WalletService.DeriveBalance(params)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(balance, message.GetResponseMessage(wh.handlerName, types.OKAY)))
+}
+
+// GetStatusChanges godoc
+// @Summary      Get a wallet's status history
+// @Description  every status change of a wallet with its reason and actor
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Wallet ID"
+// @Success      200  {object}  common.GetStatusChangesResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{id}/status-history [get]
+func (wh *walletHandler) GetStatusChanges(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	changes, err := wh.WalletService.GetStatusChanges(params)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(changes, message.GetResponseMessage(wh.handlerName, types.OKAY)))
+}
+
 // TransactionWallet godoc
 // @Summary      Transaction on a wallet by ID
-// @Description  debit or credit wallet by id
+// @Description  debit or credit wallet by id. Only services and admins may credit. A debit above APPROVAL_THRESHOLD is not posted, its funds are held until a second person approves it
 // @Tags         wallet
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string  true  "Wallet ID"
+// @Param        Idempotency-Key header string false "Replays within the retention window return the original transaction"
 // @Param wallet body common.CreateTransactionRequest true "Create transaction"
 // @Success      200  {object}  common.CreateTransactionResponse
+// @Success      202  {object}  common.GetApprovalResponse
 // @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
 // @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
 // @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
 // @Router       /wallet/{id} [patch]
 func (wh *walletHandler) TransactionWallet(c *gin.Context) {
 	var body common.CreateTransactionRequest
@@ -199,12 +403,262 @@ func (wh *walletHandler) TransactionWallet(c *gin.Context) {
 		return
 	}
 
-	transaction, err := wh.WalletService.CreateTransaction(params, body)
+	if domain.TxnType(body.TransactionType) == domain.CREDIT && !middleware.MayCredit(c) {
+		c.JSON(http.StatusForbidden, result.ReturnErrorResult(forbidden))
+		return
+	}
+
+	reference, err := idempotencyKey(c, body.Reference)
 	if err != nil {
 		wh.logger.Error(err)
 		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
 		return
 	}
+	body.Reference = reference
+
+	transaction, err := wh.WalletService.CreateTransaction(params, body, actor(c))
+	if pendingApproval(c, err, wh.handlerName) {
+		return
+	}
+
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusBadRequest), result.ReturnErrorResult(err.Error()))
+		return
+	}
 
 	c.JSON(http.StatusOK, result.ReturnSuccessResult(transaction, message.GetResponseMessage(wh.handlerName, types.CREATED_TRANSACTION)))
 }
+
+// Transfer godoc
+// @Summary      Transfer between wallets
+// @Description  debit the source wallet and credit the destination wallet atomically. A transfer above APPROVAL_THRESHOLD is not posted, its funds are held until a second person approves it
+// @Tags         transfer
+// @Accept       json
+// @Produce      json
+// @Param        Idempotency-Key header string false "Replays within the retention window return the original transfer"
+// @Param transfer body common.CreateTransferRequest true "Create transfer"
+// @Success      201  {object}  common.CreateTransferResponse
+// @Success      202  {object}  common.GetApprovalResponse
+// @Failure      400  {object}  common.Error
+// @Failure      403  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /transfers [post]
+func (wh *walletHandler) Transfer(c *gin.Context) {
+	var body common.CreateTransferRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if !middleware.Permits(c, body.SourceWalletID, middleware.WalletOwner(wh.WalletService)) {
+		c.JSON(http.StatusForbidden, result.ReturnErrorResult(forbidden))
+		return
+	}
+
+	reference, err := idempotencyKey(c, body.Reference)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+	body.Reference = reference
+
+	debit, credit, err := wh.WalletService.Transfer(body, actor(c))
+	if pendingApproval(c, err, wh.handlerName) {
+		return
+	}
+
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusBadRequest), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	transfer := common.TransferResponse{
+		Reference: debit.Reference,
+		Debit:     *debit,
+		Credit:    *credit,
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(transfer, message.GetResponseMessage(wh.handlerName, types.CREATED_TRANSFER)))
+}
+
+// ReverseTransaction godoc
+// @Summary      Reverse a transaction
+// @Description  post the exact opposite of a transaction against the same wallet
+// @Tags         transaction
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Transaction ID"
+// @Success      201  {object}  common.CreateTransactionResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      409  {object}  common.Error
+// @Failure      422  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /transactions/{id}/reverse [post]
+func (wh *walletHandler) ReverseTransaction(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	reversal, err := wh.WalletService.ReverseTransaction(params)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusBadRequest), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, result.ReturnSuccessResult(reversal, message.GetResponseMessage(wh.handlerName, types.REVERSED_TRANSACTION)))
+}
+
+// GetWallets godoc
+// @Summary      List wallets
+// @Description  paginated wallets, optionally including deleted ones
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        status           query  string  false  "Wallet status"
+// @Param        currency         query  string  false  "ISO 4217 currency code"
+// @Param        include_deleted  query  bool    false  "Also list deleted wallets"
+// @Param        limit            query  int     false  "Page size"
+// @Param        page             query  int     false  "Page number"
+// @Success      200  {object}  common.GetWalletsResponse
+// @Failure      400  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet [get]
+func (wh *walletHandler) GetWallets(c *gin.Context) {
+	var filter common.WalletFilterRequest
+	var pagination utils.Pagination
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	wallets, err := wh.WalletService.GetWallets(filter, &pagination)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(wallets, message.GetResponseMessage(wh.handlerName, types.OKAY)))
+}
+
+// RestoreWallet godoc
+// @Summary      Restore a deleted wallet
+// @Description  undo the deletion of a wallet that has not been purged yet
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "Wallet ID"
+// @Success      200  {object}  common.GetWalletResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{id}/restore [post]
+func (wh *walletHandler) RestoreWallet(c *gin.Context) {
+	var params common.GetByIDRequest
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	wallet, err := wh.WalletService.RestoreWallet(params.ID)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(wallet, message.GetResponseMessage(wh.handlerName, types.RESTORED)))
+}
+
+// GetTransactions godoc
+// @Summary      List a wallet's transactions
+// @Description  paginated transaction history of a wallet, filtered and sorted by query
+// @Tags         wallet
+// @Accept       json
+// @Produce      json
+// @Param        id                path   string  true   "Wallet ID"
+// @Param        transaction_type  query  string  false  "credit or debit"
+// @Param        purpose           query  string  false  "deposit, withdrawal, reversal or transfer"
+// @Param        min_amount        query  int     false  "Smallest amount to include"
+// @Param        max_amount        query  int     false  "Largest amount to include"
+// @Param        from              query  string  false  "Created on or after, YYYY-MM-DD"
+// @Param        to                query  string  false  "Created on or before, YYYY-MM-DD"
+// @Param        sort_by           query  string  false  "created_at or amount"
+// @Param        order             query  string  false  "asc or desc"
+// @Param        limit             query  int     false  "Page size"
+// @Param        page              query  int     false  "Page number"
+// @Param        include_deleted   query  bool    false  "Also list the history of a deleted wallet"
+// @Success      200  {object}  common.GetTransactionsResponse
+// @Failure      400  {object}  common.Error
+// @Failure      404  {object}  common.Error
+// @Failure      500  {object}  common.Error
+// @Security     BearerAuth
+// @Security     ApiKeyAuth
+// @Router       /wallet/{id}/transactions [get]
+func (wh *walletHandler) GetTransactions(c *gin.Context) {
+	var params common.GetByIDRequest
+	var filter common.TransactionFilterRequest
+	var pagination utils.Pagination
+	if err := c.ShouldBindUri(&params); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	if err := c.ShouldBindQuery(&pagination); err != nil {
+		wh.logger.Error(err)
+		c.JSON(http.StatusBadRequest, result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	transactions, err := wh.WalletService.GetTransactions(params, filter, &pagination)
+	if err != nil {
+		wh.logger.Error(err)
+		c.JSON(errorStatus(err, http.StatusInternalServerError), result.ReturnErrorResult(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, result.ReturnSuccessResult(transactions, message.GetResponseMessage(wh.handlerName, types.OKAY)))
+}
+
+// idempotencyKey resolves the key a request is deduplicated by from its header and body reference
+func idempotencyKey(c *gin.Context, reference string) (string, error) {
+	var header common.IdempotencyHeader
+	if err := c.ShouldBindHeader(&header); err != nil {
+		return "", err
+	}
+	return header.IdempotencyKey(reference)
+}
@@ -7,20 +7,28 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 	"wallet_engine/internals/core/domain"
 
 	"github.com/gin-gonic/gin"
+	uuid "github.com/satori/go.uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 
 	"wallet_engine/internals/common"
 	"wallet_engine/internals/core/services"
+	"wallet_engine/internals/middleware"
 	"wallet_engine/internals/repositories"
+	"wallet_engine/pkg/config"
 	datastore "wallet_engine/pkg/database"
+	"wallet_engine/pkg/fx"
 	"wallet_engine/pkg/logger"
+	"wallet_engine/pkg/nuban"
+	"wallet_engine/pkg/utils"
 )
 
 var (
@@ -29,22 +37,48 @@ var (
 	logging               = logger.NewLogger(log.New()).MakeLogger(filepath.Join("..", "..", "logs", "info"), true)
 	walletRepository      = repositories.NewRepository[domain.Wallet](DBConnection)
 	transactionRepository = repositories.NewRepository[domain.Transaction](DBConnection)
-	walletService         = services.NewWalletService(*walletRepository, *transactionRepository, logging, DBConnection)
+	idempotencyRepository = repositories.NewRepository[domain.IdempotencyKey](DBConnection)
+	quoteRepository       = repositories.NewRepository[domain.FXQuote](DBConnection)
+	limitRepository       = repositories.NewRepository[domain.TierLimit](DBConnection)
+	statusRepository      = repositories.NewRepository[domain.WalletStatusChange](DBConnection)
+	ledgerService         = services.NewLedgerService(*repositories.NewRepository[domain.LedgerAccount](DBConnection), *repositories.NewRepository[domain.JournalEntry](DBConnection), *repositories.NewRepository[domain.Posting](DBConnection), *transactionRepository, logging)
+	feeRepository         = repositories.NewRepository[domain.FeeSchedule](DBConnection)
+	customerRepository    = repositories.NewRepository[domain.Customer](DBConnection)
+	approvalRepository    = repositories.NewRepository[domain.ApprovalRequest](DBConnection)
+	walletService         = services.NewWalletService(*walletRepository, *transactionRepository, *idempotencyRepository, *quoteRepository, *limitRepository, *statusRepository, *feeRepository, *customerRepository, *approvalRepository, ledgerService, nuban.NewNUBAN("000"), logging, DBConnection)
 	handler               = NewWalletHandler(walletService, logging, "Wallet")
+	rates, _              = fx.NewStaticRateProvider(map[string]string{"USD/NGN": "1500"})
+	fxService             = services.NewFXService(*quoteRepository, rates, logging)
+	quoteHandler          = NewFXHandler(fxService, logging, "Quote")
+	holdRepository        = repositories.NewRepository[domain.Hold](DBConnection)
+	holdService           = services.NewHoldService(*walletRepository, *holdRepository, walletService, logging, DBConnection)
+	holdsHandler          = NewHoldHandler(holdService, logging, "Hold")
 )
 
+// SetupRouter returns a router whose requests act for an admin, tests that need another
+// principal set their own further down the chain
 func SetupRouter() *gin.Engine {
 	router := gin.Default()
+	router.Use(func(c *gin.Context) {
+		middleware.SetPrincipal(c, &domain.Principal{Subject: "admin", Scopes: []string{domain.ADMIN_SCOPE}})
+	})
 	return router
 }
 
 func createWallet(t *testing.T) *common.CreateWalletResponse {
-	r := SetupRouter()
-	r.POST("/v1/wallet", handler.CreateWallet)
-	entity := common.CreateWalletRequest{
+	return createWalletWith(t, common.CreateWalletRequest{
 		Status: "active",
+	})
+}
+
+func createWalletWith(t *testing.T, entity common.CreateWalletRequest) *common.CreateWalletResponse {
+	if entity.CustomerID == "" {
+		entity.CustomerID = createCustomer(t).ID.String()
 	}
 
+	r := SetupRouter()
+	r.POST("/v1/wallet", handler.CreateWallet)
+
 	jsonValue, _ := json.Marshal(entity)
 	request, err := http.NewRequest("POST", "/v1/wallet", bytes.NewBuffer(jsonValue))
 	if err != nil {
@@ -70,6 +104,22 @@ func TestWalletHandler_CreateWallet(t *testing.T) {
 	require.NotEmpty(t, wallet)
 }
 
+func TestWalletHandler_CreateWalletInitialStatus(t *testing.T) {
+	r := SetupRouter()
+	r.POST("/v1/wallet", handler.CreateWallet)
+
+	for _, status := range []string{"inactive", "frozen", "closed"} {
+		jsonValue, _ := json.Marshal(common.CreateWalletRequest{CustomerID: createCustomer(t).ID.String(), Status: status})
+		request, err := http.NewRequest("POST", "/v1/wallet", bytes.NewBuffer(jsonValue))
+		require.NoError(t, err)
+
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+
+		require.Equal(t, http.StatusBadRequest, response.Code, status)
+	}
+}
+
 func TestWalletHandler_GetWalletByID(t *testing.T) {
 	wallet := createWallet(t)
 
@@ -155,3 +205,821 @@ func TestWalletHandler_UpdateWallet(t *testing.T) {
 
 	require.Equal(t, http.StatusOK, response.Code)
 }
+
+func creditWallet(t *testing.T, id string, amount int64) *common.CreateTransactionResponse {
+	r := SetupRouter()
+	r.PATCH("/v1/wallet/:id", handler.TransactionWallet)
+
+	body := common.CreateTransactionRequest{
+		TransactionType: "credit",
+		Purpose:         "deposit",
+		Amount:          amount,
+		AccountID:       id,
+	}
+
+	jsonValue, _ := json.Marshal(body)
+	request, err := http.NewRequest("PATCH", fmt.Sprintf("/v1/wallet/%v", id), bytes.NewBuffer(jsonValue))
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var transaction *common.CreateTransactionResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &transaction))
+
+	return transaction
+}
+
+func transfer(t *testing.T, body common.CreateTransferRequest) *httptest.ResponseRecorder {
+	r := SetupRouter()
+	r.POST("/v1/transfers", handler.Transfer)
+
+	jsonValue, _ := json.Marshal(body)
+	request, err := http.NewRequest("POST", "/v1/transfers", bytes.NewBuffer(jsonValue))
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestWalletHandler_Transfer(t *testing.T) {
+	source := createWallet(t)
+	destination := createWallet(t)
+	creditWallet(t, source.Data.ID.String(), 1000)
+
+	response := transfer(t, common.CreateTransferRequest{
+		SourceWalletID:      source.Data.ID.String(),
+		DestinationWalletID: destination.Data.ID.String(),
+		Amount:              400,
+	})
+
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var resp common.CreateTransferResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &resp))
+	require.NotEmpty(t, resp.Data.Reference)
+	require.Equal(t, resp.Data.Reference, resp.Data.Debit.Reference)
+	require.Equal(t, resp.Data.Reference, resp.Data.Credit.Reference)
+	require.Equal(t, int64(600), resp.Data.Debit.BalanceAfter)
+	require.Equal(t, int64(400), resp.Data.Credit.BalanceAfter)
+
+	sourceWallet, err := walletService.GetWalletByID(source.Data.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, int64(600), sourceWallet.Balance)
+
+	destinationWallet, err := walletService.GetWalletByID(destination.Data.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, int64(400), destinationWallet.Balance)
+}
+
+func TestWalletHandler_TransferInsufficientBalance(t *testing.T) {
+	source := createWallet(t)
+	destination := createWallet(t)
+	creditWallet(t, source.Data.ID.String(), 100)
+
+	response := transfer(t, common.CreateTransferRequest{
+		SourceWalletID:      source.Data.ID.String(),
+		DestinationWalletID: destination.Data.ID.String(),
+		Amount:              400,
+	})
+
+	require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+	sourceWallet, err := walletService.GetWalletByID(source.Data.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, int64(100), sourceWallet.Balance)
+
+	destinationWallet, err := walletService.GetWalletByID(destination.Data.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, int64(0), destinationWallet.Balance)
+}
+
+func TestWalletHandler_TransactionWalletValidation(t *testing.T) {
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	creditWallet(t, id, 100)
+
+	// anything other than a credit or a debit is refused before it can touch the balance
+	response := transactWithKey(t, id, "", common.CreateTransactionRequest{
+		TransactionType: "refund",
+		Purpose:         "deposit",
+		Amount:          100,
+		AccountID:       id,
+	})
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	// the purposes the engine posts itself, such as fees and reversals, cannot be claimed by a client
+	response = transactWithKey(t, id, "", common.CreateTransactionRequest{
+		TransactionType: "credit",
+		Purpose:         "reversal",
+		Amount:          100,
+		AccountID:       id,
+	})
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	// a negative debit would otherwise credit the wallet
+	response = transactWithKey(t, id, "", common.CreateTransactionRequest{
+		TransactionType: "debit",
+		Purpose:         "withdrawal",
+		Amount:          -500,
+		AccountID:       id,
+	})
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	stored, err := walletService.GetWalletByID(id)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), stored.Balance)
+}
+
+func TestWalletHandler_TransferToSameWallet(t *testing.T) {
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	creditWallet(t, id, 100)
+
+	response := transfer(t, common.CreateTransferRequest{
+		SourceWalletID:      id,
+		DestinationWalletID: id,
+		Amount:              100,
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+	// the same wallet spelt in another case must not become two copies of one row
+	_, _, err := walletService.Transfer(common.CreateTransferRequest{
+		SourceWalletID:      id,
+		DestinationWalletID: strings.ToUpper(id),
+		Amount:              100,
+	}, "")
+	require.ErrorIs(t, err, domain.ErrSameWallet)
+
+	stored, err := walletService.GetWalletByID(id)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), stored.Balance)
+}
+
+func transactWithKey(t *testing.T, id, key string, body common.CreateTransactionRequest) *httptest.ResponseRecorder {
+	r := SetupRouter()
+	r.PATCH("/v1/wallet/:id", handler.TransactionWallet)
+
+	jsonValue, _ := json.Marshal(body)
+	request, err := http.NewRequest("PATCH", fmt.Sprintf("/v1/wallet/%v", id), bytes.NewBuffer(jsonValue))
+	require.NoError(t, err)
+	request.Header.Set("Idempotency-Key", key)
+
+	response := httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestWalletHandler_TransactionWalletIdempotency(t *testing.T) {
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	key := uuid.NewV4().String()
+
+	body := common.CreateTransactionRequest{
+		TransactionType: "credit",
+		Purpose:         "deposit",
+		Amount:          250,
+		AccountID:       id,
+	}
+
+	first := transactWithKey(t, id, key, body)
+	require.Equal(t, http.StatusOK, first.Code)
+
+	replay := transactWithKey(t, id, key, body)
+	require.Equal(t, http.StatusOK, replay.Code)
+
+	var original, replayed common.CreateTransactionResponse
+	require.NoError(t, json.Unmarshal(first.Body.Bytes(), &original))
+	require.NoError(t, json.Unmarshal(replay.Body.Bytes(), &replayed))
+	require.Equal(t, original.Data.ID, replayed.Data.ID)
+
+	stored, err := walletService.GetWalletByID(id)
+	require.NoError(t, err)
+	require.Equal(t, int64(250), stored.Balance)
+
+	body.Amount = 300
+	conflict := transactWithKey(t, id, key, body)
+	require.Equal(t, http.StatusUnprocessableEntity, conflict.Code)
+
+	// keys are scoped to the wallet, another wallet's client may happen to pick the same one
+	other := createWallet(t).Data.ID.String()
+	body.AccountID = other
+	elsewhere := transactWithKey(t, other, key, body)
+	require.Equal(t, http.StatusOK, elsewhere.Code)
+
+	var separate common.CreateTransactionResponse
+	require.NoError(t, json.Unmarshal(elsewhere.Body.Bytes(), &separate))
+	require.NotEqual(t, original.Data.ID, separate.Data.ID)
+	require.Equal(t, other, separate.Data.WalletID.String())
+}
+
+func reverse(t *testing.T, id string) *httptest.ResponseRecorder {
+	r := SetupRouter()
+	r.POST("/v1/transactions/:id/reverse", handler.ReverseTransaction)
+
+	request, err := http.NewRequest("POST", fmt.Sprintf("/v1/transactions/%v/reverse", id), nil)
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	return response
+}
+
+func TestWalletHandler_ReverseTransaction(t *testing.T) {
+	wallet := createWallet(t)
+	credit := creditWallet(t, wallet.Data.ID.String(), 700)
+
+	response := reverse(t, credit.Data.ID.String())
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var reversal common.CreateTransactionResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &reversal))
+	require.Equal(t, domain.TxnType(domain.DEBIT), reversal.Data.TransactionType)
+	require.Equal(t, domain.PurposeType(domain.REVERSAL), reversal.Data.Purpose)
+	require.Equal(t, credit.Data.ID, *reversal.Data.ReversalOf)
+
+	stored, err := walletService.GetWalletByID(wallet.Data.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, int64(0), stored.Balance)
+
+	require.Equal(t, http.StatusConflict, reverse(t, credit.Data.ID.String()).Code)
+	require.Equal(t, http.StatusUnprocessableEntity, reverse(t, reversal.Data.ID.String()).Code)
+}
+
+func TestWalletHandler_ReverseTransferLeg(t *testing.T) {
+	source := createWallet(t)
+	destination := createWallet(t)
+	creditWallet(t, source.Data.ID.String(), 1000)
+
+	body := common.CreateTransferRequest{
+		SourceWalletID:      source.Data.ID.String(),
+		DestinationWalletID: destination.Data.ID.String(),
+		Amount:              400,
+		Reference:           uuid.NewV4().String(),
+	}
+
+	var original common.CreateTransferResponse
+	require.NoError(t, json.Unmarshal(transfer(t, body).Body.Bytes(), &original))
+
+	response := reverse(t, original.Data.Debit.ID.String())
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var reversal common.CreateTransactionResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &reversal))
+	require.Empty(t, reversal.Data.Reference)
+
+	// the reversal credits the source, it must never be replayed as the transfer's credit leg
+	response = transfer(t, body)
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var replayed common.CreateTransferResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &replayed))
+	require.Equal(t, original.Data.Debit.ID, replayed.Data.Debit.ID)
+	require.Equal(t, original.Data.Credit.ID, replayed.Data.Credit.ID)
+}
+
+func TestWalletHandler_GetTransactions(t *testing.T) {
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	creditWallet(t, id, 100)
+	creditWallet(t, id, 200)
+	creditWallet(t, id, 300)
+
+	r := SetupRouter()
+	r.GET("/v1/wallet/:id/transactions", handler.GetTransactions)
+
+	endpoint := fmt.Sprintf("/v1/wallet/%v/transactions?transaction_type=credit&min_amount=150&sort_by=amount&order=asc", id)
+	request, err := http.NewRequest("GET", endpoint, nil)
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var resp common.GetTransactionsResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &resp))
+	require.Equal(t, int64(2), resp.Data.TotalRows)
+	require.Equal(t, 1, resp.Data.TotalPages)
+	require.Len(t, resp.Data.Rows, 2)
+	require.Equal(t, int64(200), resp.Data.Rows[0].Amount)
+	require.Equal(t, int64(300), resp.Data.Rows[1].Amount)
+
+	request, err = http.NewRequest("GET", fmt.Sprintf("/v1/wallet/%v/transactions?limit=100000", id), nil)
+	require.NoError(t, err)
+
+	response = httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &resp))
+	require.Equal(t, utils.MaxLimit, resp.Data.Limit)
+
+	for _, query := range []string{"min_amount=300&max_amount=100", "from=2024-02-01&to=2024-01-01"} {
+		request, err = http.NewRequest("GET", fmt.Sprintf("/v1/wallet/%v/transactions?%v", id, query), nil)
+		require.NoError(t, err)
+
+		response = httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+
+		require.Equal(t, http.StatusBadRequest, response.Code, query)
+	}
+}
+
+func TestWalletHandler_GetWalletWithTransactions(t *testing.T) {
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	creditWallet(t, id, 100)
+	creditWallet(t, id, 200)
+
+	r := SetupRouter()
+	r.GET("/v1/wallet/:id", handler.GetWalletByID)
+
+	request, err := http.NewRequest("GET", fmt.Sprintf("/v1/wallet/%v?include=transactions", id), nil)
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var resp common.CreateWalletResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &resp))
+	require.Len(t, resp.Data.Transactions, 2)
+	for _, transaction := range resp.Data.Transactions {
+		require.Equal(t, wallet.Data.ID, transaction.WalletID)
+	}
+}
+
+func TestWalletHandler_GetWalletByAccountNumber(t *testing.T) {
+	wallet := createWallet(t)
+
+	r := SetupRouter()
+	r.GET("/v1/accounts/:account_number", handler.GetWalletByAccountNumber)
+
+	request, err := http.NewRequest("GET", fmt.Sprintf("/v1/accounts/%v", wallet.Data.AccountID), nil)
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var resp common.CreateWalletResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &resp))
+	require.Equal(t, wallet.Data.ID, resp.Data.ID)
+
+	// a number no wallet holds with a flipped check digit is a mistyped one
+	invalid := wallet.Data.AccountID/10*10 + (wallet.Data.AccountID%10+1)%10
+	request, err = http.NewRequest("GET", fmt.Sprintf("/v1/accounts/%v", invalid), nil)
+	require.NoError(t, err)
+
+	response = httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusBadRequest, response.Code)
+
+	// a wallet numbered before check digits existed can still be found by its number
+	require.NoError(t, DBConnection.Model(&domain.Wallet{}).Where("id = ?", wallet.Data.ID).Update("account_id", invalid).Error)
+
+	request, err = http.NewRequest("GET", fmt.Sprintf("/v1/accounts/%v", invalid), nil)
+	require.NoError(t, err)
+
+	response = httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusOK, response.Code)
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &resp))
+	require.Equal(t, wallet.Data.ID, resp.Data.ID)
+}
+
+func TestDatabase_RenumberSharedAccounts(t *testing.T) {
+	first := createWallet(t).Data
+	second := createWallet(t).Data
+
+	// wallets created before the unique index could share a number
+	require.NoError(t, DBConnection.Migrator().DropIndex(&domain.Wallet{}, "idx_wallets_account_number"))
+	require.NoError(t, DBConnection.Model(&domain.Wallet{}).Where("id = ?", second.ID).Update("account_id", first.AccountID).Error)
+
+	require.NoError(t, db.MigrateAll(DBConnection))
+	require.True(t, DBConnection.Migrator().HasIndex(&domain.Wallet{}, "idx_wallets_account_number"))
+
+	renumbered, err := walletService.GetWalletByID(first.ID.String())
+	require.NoError(t, err)
+	other, err := walletService.GetWalletByID(second.ID.String())
+	require.NoError(t, err)
+
+	require.NotEqual(t, first.AccountID, renumbered.AccountID)
+	require.NotEqual(t, first.AccountID, other.AccountID)
+	require.NotEqual(t, renumbered.AccountID, other.AccountID)
+}
+
+func TestWalletHandler_CurrencyMismatch(t *testing.T) {
+	naira := createWallet(t)
+	dollar := createWalletWith(t, common.CreateWalletRequest{
+		Status:   "active",
+		Currency: "usd",
+	})
+	require.Equal(t, domain.DefaultCurrency, naira.Data.Currency)
+	require.Equal(t, domain.Currency("USD"), dollar.Data.Currency)
+
+	response := transactWithKey(t, dollar.Data.ID.String(), uuid.NewV4().String(), common.CreateTransactionRequest{
+		TransactionType: "credit",
+		Purpose:         "deposit",
+		Amount:          100,
+		Currency:        "NGN",
+		AccountID:       dollar.Data.ID.String(),
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+	creditWallet(t, naira.Data.ID.String(), 100)
+	response = transfer(t, common.CreateTransferRequest{
+		SourceWalletID:      naira.Data.ID.String(),
+		DestinationWalletID: dollar.Data.ID.String(),
+		Amount:              100,
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+}
+
+func createQuote(t *testing.T, body common.CreateQuoteRequest) *common.CreateQuoteResponse {
+	r := SetupRouter()
+	r.POST("/v1/fx/quotes", quoteHandler.CreateQuote)
+
+	jsonValue, _ := json.Marshal(body)
+	request, err := http.NewRequest("POST", "/v1/fx/quotes", bytes.NewBuffer(jsonValue))
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+
+	r.ServeHTTP(response, request)
+
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var quote *common.CreateQuoteResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &quote))
+
+	return quote
+}
+
+func TestWalletHandler_ConversionTransfer(t *testing.T) {
+	naira := createWallet(t)
+	dollar := createWalletWith(t, common.CreateWalletRequest{
+		Status:   "active",
+		Currency: "USD",
+	})
+	creditWallet(t, naira.Data.ID.String(), 150000)
+
+	quote := createQuote(t, common.CreateQuoteRequest{
+		SourceCurrency:      "NGN",
+		DestinationCurrency: "USD",
+		SourceAmount:        150000,
+	})
+	require.Equal(t, int64(100), quote.Data.DestinationAmount)
+
+	body := common.CreateTransferRequest{
+		SourceWalletID:      naira.Data.ID.String(),
+		DestinationWalletID: dollar.Data.ID.String(),
+		Amount:              150000,
+		QuoteID:             quote.Data.ID.String(),
+	}
+
+	response := transfer(t, body)
+	require.Equal(t, http.StatusCreated, response.Code)
+
+	var resp common.CreateTransferResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &resp))
+	require.Equal(t, domain.Currency("USD"), resp.Data.Credit.Currency)
+	require.Equal(t, int64(100), resp.Data.Credit.Amount)
+	require.Equal(t, quote.Data.Rate, resp.Data.Debit.FXRate)
+	require.Equal(t, int64(150000), resp.Data.Credit.SourceAmount)
+	require.Equal(t, int64(100), resp.Data.Debit.DestinationAmount)
+
+	stored, err := walletService.GetWalletByID(dollar.Data.ID.String())
+	require.NoError(t, err)
+	require.Equal(t, int64(100), stored.Balance)
+
+	// a quote settles exactly one transfer
+	creditWallet(t, naira.Data.ID.String(), 150000)
+	require.Equal(t, http.StatusUnprocessableEntity, transfer(t, body).Code)
+}
+
+func TestWalletHandler_TransactionLimits(t *testing.T) {
+	// debits this large would otherwise wait on approval before reaching the daily limit
+	threshold := "5000000"
+	previous := config.Instance
+	config.Instance = &config.Config{ApprovalThreshold: &threshold}
+	t.Cleanup(func() { config.Instance = previous })
+
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+
+	transact := func(transactionType string, amount int64) *httptest.ResponseRecorder {
+		return transactWithKey(t, id, uuid.NewV4().String(), common.CreateTransactionRequest{
+			TransactionType: transactionType,
+			Purpose:         "deposit",
+			Amount:          amount,
+			AccountID:       id,
+		})
+	}
+
+	response := transact("credit", 6000000)
+	require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	require.Contains(t, response.Body.String(), "max single transaction")
+
+	require.Equal(t, http.StatusOK, transact("credit", 4000000).Code)
+	require.Equal(t, http.StatusOK, transact("credit", 4000000).Code)
+	require.Equal(t, http.StatusOK, transact("debit", 4000000).Code)
+
+	response = transact("debit", 2000000)
+	require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	require.Contains(t, response.Body.String(), "daily debit total")
+}
+
+func setStatus(t *testing.T, id, status string) *httptest.ResponseRecorder {
+	r := SetupRouter()
+	r.PATCH("/v1/wallet/:id", handler.UpdateWallet)
+
+	endpoint := fmt.Sprintf("/v1/wallet/%v?status=%v&reason=test&actor=ops", id, status)
+	request, err := http.NewRequest("PATCH", endpoint, nil)
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	return response
+}
+
+func TestWalletHandler_WalletLifecycle(t *testing.T) {
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	creditWallet(t, id, 1000)
+
+	require.Equal(t, http.StatusOK, setStatus(t, id, "frozen").Code)
+
+	response := transactWithKey(t, id, uuid.NewV4().String(), common.CreateTransactionRequest{
+		TransactionType: "credit",
+		Purpose:         "deposit",
+		Amount:          100,
+		AccountID:       id,
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+	require.Equal(t, http.StatusUnprocessableEntity, setStatus(t, id, "pending").Code)
+	require.Equal(t, http.StatusBadRequest, setStatus(t, id, "dormant").Code)
+	require.Equal(t, http.StatusUnprocessableEntity, setStatus(t, id, "closed").Code)
+
+	require.Equal(t, http.StatusOK, setStatus(t, id, "active").Code)
+
+	var changes []domain.WalletStatusChange
+	require.NoError(t, DBConnection.Where("wallet_id = ?", id).Order("created_at").Find(&changes).Error)
+	require.Len(t, changes, 2)
+	require.Equal(t, domain.FROZEN, changes[0].To)
+	require.Equal(t, "ops", changes[0].Actor)
+}
+
+func closeWallet(t *testing.T, id string, body common.CloseWalletRequest) *httptest.ResponseRecorder {
+	r := SetupRouter()
+	r.POST("/v1/wallet/:id/close", handler.CloseWallet)
+
+	jsonValue, _ := json.Marshal(body)
+	request, err := http.NewRequest("POST", fmt.Sprintf("/v1/wallet/%v/close", id), bytes.NewBuffer(jsonValue))
+	require.NoError(t, err)
+
+	response := httptest.NewRecorder()
+	r.ServeHTTP(response, request)
+	return response
+}
+
+func TestWalletHandler_CloseWallet(t *testing.T) {
+	source := createWallet(t)
+	destination := createWallet(t)
+	id := source.Data.ID.String()
+	creditWallet(t, id, 2500)
+
+	response := closeWallet(t, id, common.CloseWalletRequest{Reason: "customer request"})
+	require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+
+	response = closeWallet(t, id, common.CloseWalletRequest{
+		DestinationWalletID: destination.Data.ID.String(),
+		Reason:              "customer request",
+	})
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var closed common.CloseWalletResponse
+	require.NoError(t, json.Unmarshal(response.Body.Bytes(), &closed))
+	require.Equal(t, domain.CLOSED, closed.Data.Wallet.Status)
+	require.Equal(t, int64(0), closed.Data.Wallet.Balance)
+	require.NotNil(t, closed.Data.Sweep)
+	require.Equal(t, int64(2500), closed.Data.Sweep.Credit.BalanceAfter)
+
+	response = transactWithKey(t, id, uuid.NewV4().String(), common.CreateTransactionRequest{
+		TransactionType: "credit",
+		Purpose:         "deposit",
+		Amount:          100,
+		AccountID:       id,
+	})
+	require.Equal(t, http.StatusUnprocessableEntity, response.Code)
+	require.Equal(t, http.StatusUnprocessableEntity, setStatus(t, id, "active").Code)
+}
+
+func TestWalletHandler_RestoreWallet(t *testing.T) {
+	r := SetupRouter()
+	r.GET("/v1/wallet", handler.GetWallets)
+	r.GET("/v1/wallet/:id", handler.GetWalletByID)
+	r.DELETE("/v1/wallet/:id", handler.DeleteWallet)
+	r.POST("/v1/wallet/:id/restore", handler.RestoreWallet)
+
+	serve := func(method, endpoint string) *httptest.ResponseRecorder {
+		request, err := http.NewRequest(method, endpoint, nil)
+		require.NoError(t, err)
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		return response
+	}
+
+	wallet := createWalletWith(t, common.CreateWalletRequest{Status: "active", Currency: "GBP"})
+	id := wallet.Data.ID.String()
+
+	require.Equal(t, http.StatusNoContent, serve("DELETE", "/v1/wallet/"+id).Code)
+	require.Equal(t, http.StatusNotFound, serve("GET", "/v1/wallet/"+id).Code)
+
+	var row domain.Wallet
+	require.NoError(t, DBConnection.Unscoped().Where("id = ?", id).First(&row).Error)
+	require.True(t, row.DeletedAt.Valid)
+
+	listing := serve("GET", "/v1/wallet?currency=GBP&limit=100")
+	require.Equal(t, http.StatusOK, listing.Code)
+	require.NotContains(t, listing.Body.String(), id)
+
+	listing = serve("GET", "/v1/wallet?currency=GBP&limit=100&include_deleted=true")
+	require.Equal(t, http.StatusOK, listing.Code)
+	require.Contains(t, listing.Body.String(), id)
+
+	require.Equal(t, http.StatusOK, serve("POST", "/v1/wallet/"+id+"/restore").Code)
+	require.Equal(t, http.StatusOK, serve("GET", "/v1/wallet/"+id).Code)
+	require.Equal(t, http.StatusNotFound, serve("POST", "/v1/wallet/"+id+"/restore").Code)
+}
+
+func TestWalletHandler_PurgeWallets(t *testing.T) {
+	empty := createWallet(t)
+	used := createWallet(t)
+	creditWallet(t, used.Data.ID.String(), 100)
+
+	lapsed := time.Now().Add(-365 * 24 * time.Hour)
+	for _, id := range []uuid.UUID{empty.Data.ID, used.Data.ID} {
+		require.NoError(t, DBConnection.Model(&domain.Wallet{}).Where("id = ?", id).Update("deleted_at", lapsed).Error)
+	}
+
+	_, err := walletService.PurgeWallets()
+	require.NoError(t, err)
+
+	var remaining int64
+	require.NoError(t, DBConnection.Unscoped().Model(&domain.Wallet{}).Where("id = ?", empty.Data.ID).Count(&remaining).Error)
+	require.Equal(t, int64(0), remaining)
+
+	require.NoError(t, DBConnection.Unscoped().Model(&domain.Wallet{}).Where("id = ?", used.Data.ID).Count(&remaining).Error)
+	require.Equal(t, int64(1), remaining)
+}
+
+func TestWalletHandler_DeriveBalance(t *testing.T) {
+	r := SetupRouter()
+	r.GET("/v1/wallet/:id/balance-check", handler.DeriveBalance)
+
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	credit := creditWallet(t, id, 1000)
+	require.Equal(t, http.StatusOK, transactWithKey(t, id, uuid.NewV4().String(), common.CreateTransactionRequest{
+		TransactionType: "debit",
+		Purpose:         "withdrawal",
+		Amount:          300,
+		AccountID:       id,
+	}).Code)
+
+	check := func() common.LedgerBalance {
+		request, err := http.NewRequest("GET", fmt.Sprintf("/v1/wallet/%v/balance-check", id), nil)
+		require.NoError(t, err)
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		var body common.GetLedgerBalanceResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		return body.Data
+	}
+
+	balance := check()
+	require.True(t, balance.Balanced)
+	require.Equal(t, int64(700), balance.DerivedBalance)
+	require.Equal(t, int64(2), balance.Entries)
+
+	require.NoError(t, DBConnection.Model(&domain.Wallet{}).Where("id = ?", id).Update("balance", 750).Error)
+
+	balance = check()
+	require.False(t, balance.Balanced)
+	require.Equal(t, int64(50), balance.Drift)
+
+	entry, err := transactionRepository.GetByID(credit.Data.ID.String())
+	require.NoError(t, err)
+	entry.Amount = 5000
+	require.ErrorIs(t, transactionRepository.Update(entry), domain.ErrImmutableTransaction)
+	require.ErrorIs(t, transactionRepository.Delete(credit.Data.ID.String(), domain.Transaction{}), domain.ErrImmutableTransaction)
+}
+
+func TestReconciliationService_Reconcile(t *testing.T) {
+	reconciliation := services.NewReconciliationService(*walletRepository, *transactionRepository, logging)
+
+	clean := createWallet(t)
+	creditWallet(t, clean.Data.ID.String(), 400)
+
+	broken := createWallet(t)
+	creditWallet(t, broken.Data.ID.String(), 1000)
+	last := creditWallet(t, broken.Data.ID.String(), 500)
+	require.NoError(t, DBConnection.Exec("UPDATE transactions SET balance_before = 900 WHERE id = ?", last.Data.ID).Error)
+	require.NoError(t, DBConnection.Model(&domain.Wallet{}).Where("id = ?", broken.Data.ID).Update("balance", 1600).Error)
+
+	report, err := reconciliation.Reconcile()
+	require.NoError(t, err)
+
+	kinds := map[uuid.UUID][]domain.DiscrepancyKind{}
+	for _, discrepancy := range report.Discrepancies {
+		kinds[discrepancy.WalletID] = append(kinds[discrepancy.WalletID], discrepancy.Kind)
+	}
+	require.Empty(t, kinds[clean.Data.ID])
+	require.ElementsMatch(t, []domain.DiscrepancyKind{
+		domain.BROKEN_CHAIN, domain.ENTRY_MISMATCH, domain.BALANCE_MISMATCH, domain.JOURNAL_MISMATCH, domain.TAMPERED,
+	}, kinds[broken.Data.ID])
+
+	paths, err := reconciliation.WriteReport(report, t.TempDir())
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+
+	csvReport, err := os.ReadFile(paths[1])
+	require.NoError(t, err)
+	require.Contains(t, string(csvReport), broken.Data.ID.String()+",")
+}
+
+func TestReconciliationHandler_VerifyChain(t *testing.T) {
+	r := SetupRouter()
+	chainHandler := NewReconciliationHandler(services.NewReconciliationService(*walletRepository, *transactionRepository, logging), logging, "Chain")
+	r.GET("/v1/wallet/:id/verify", chainHandler.VerifyChain)
+
+	wallet := createWallet(t)
+	id := wallet.Data.ID.String()
+	first := creditWallet(t, id, 100)
+	second := creditWallet(t, id, 200)
+	third := creditWallet(t, id, 300)
+	require.Equal(t, first.Data.Hash, second.Data.PreviousHash)
+
+	verify := func() common.ChainVerification {
+		request, err := http.NewRequest("GET", fmt.Sprintf("/v1/wallet/%v/verify", id), nil)
+		require.NoError(t, err)
+		response := httptest.NewRecorder()
+		r.ServeHTTP(response, request)
+		require.Equal(t, http.StatusOK, response.Code)
+
+		var body common.GetChainVerificationResponse
+		require.NoError(t, json.Unmarshal(response.Body.Bytes(), &body))
+		return body.Data
+	}
+
+	verification := verify()
+	require.True(t, verification.Intact)
+	require.Equal(t, int64(3), verification.Verified)
+
+	// a hash recomputed without the key does not match
+	previous := config.Instance
+	t.Cleanup(func() { config.Instance = previous })
+	key := "another key"
+	config.Instance = &config.Config{LedgerHashKey: &key}
+	require.False(t, verify().Intact)
+	config.Instance = previous
+
+	// every remaining link is sound once the latest transaction is dropped, only the head tells
+	require.NoError(t, DBConnection.Exec("DELETE FROM transactions WHERE id = ?", third.Data.ID).Error)
+
+	verification = verify()
+	require.False(t, verification.Intact)
+	require.Equal(t, int64(2), verification.Verified)
+	require.Nil(t, verification.FirstTampered)
+
+	require.NoError(t, DBConnection.Exec("UPDATE transactions SET amount = 250 WHERE id = ?", second.Data.ID).Error)
+
+	verification = verify()
+	require.False(t, verification.Intact)
+	require.Equal(t, second.Data.ID, *verification.FirstTampered)
+	require.Equal(t, int64(1), verification.Verified)
+}
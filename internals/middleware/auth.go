@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	uuid "github.com/satori/go.uuid"
+	log "github.com/sirupsen/logrus"
+
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/pkg/token"
+	"wallet_engine/pkg/utils"
+)
+
+const (
+	// principalKey is the context key the authenticated principal is stored under
+	principalKey = "principal"
+
+	// APIKeyHeader is the header a service sends its API key in
+	APIKeyHeader = "X-API-Key"
+)
+
+var result utils.Result
+
+// Source reads the id of the resource a request acts on
+type Source func(c *gin.Context) (string, error)
+
+// OwnerLookup returns the customer that holds the resource with id
+type OwnerLookup func(id string) (uuid.UUID, error)
+
+// Authenticate requires either an API key or a valid bearer token on every request and stores
+// the principal it names in the context
+func Authenticate(verifier *token.Verifier, keys ports.IAPIKeyService, l *log.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader(APIKeyHeader); key != "" {
+			apiKey, err := keys.Authenticate(key)
+			if err != nil {
+				l.Warn(err)
+				c.AbortWithStatusJSON(http.StatusUnauthorized, result.ReturnErrorResult("invalid api key"))
+				return
+			}
+
+			SetPrincipal(c, &domain.Principal{Subject: "apikey:" + apiKey.Prefix, Scopes: apiKey.ScopeList(), Service: true})
+			c.Next()
+			return
+		}
+
+		raw := strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+		if raw == "" || raw == c.GetHeader("Authorization") {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, result.ReturnErrorResult("a bearer token or api key is required"))
+			return
+		}
+
+		claims, err := verifier.Verify(raw)
+		if err != nil {
+			l.Warn(err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, result.ReturnErrorResult("invalid token"))
+			return
+		}
+
+		SetPrincipal(c, &domain.Principal{Subject: claims.Subject, Scopes: claims.Scopes(), Role: domain.Role(claims.Role)})
+		c.Next()
+	}
+}
+
+// SetPrincipal stores the principal a request acts for in its context
+func SetPrincipal(c *gin.Context, principal *domain.Principal) {
+	c.Set(principalKey, principal)
+}
+
+// GetPrincipal returns the principal a request acts for, nil when it was not authenticated
+func GetPrincipal(c *gin.Context) *domain.Principal {
+	if value, ok := c.Get(principalKey); ok {
+		if principal, ok := value.(*domain.Principal); ok {
+			return principal
+		}
+	}
+	return nil
+}
+
+// RequireScope only lets through principals allowed the capability scope grants
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := GetPrincipal(c)
+		if principal == nil || !principal.Allows(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, result.ReturnErrorResult(fmt.Sprintf("the %v scope is required", scope)))
+			return
+		}
+		c.Next()
+	}
+}
+
+// MayCredit reports whether the principal a request acts for may credit a wallet with funds
+// from outside the engine
+func MayCredit(c *gin.Context) bool {
+	principal := GetPrincipal(c)
+	return principal != nil && principal.MayCredit()
+}
+
+// RequireRole only lets through staff acting in min or a role above it
+func RequireRole(min domain.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := GetPrincipal(c)
+		if principal == nil || !principal.StaffRole().AtLeast(min) {
+			c.AbortWithStatusJSON(http.StatusForbidden, result.ReturnErrorResult(fmt.Sprintf("the %v role is required", min)))
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireAdmin only lets admin principals through
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := GetPrincipal(c)
+		if principal == nil || !principal.IsAdmin() {
+			c.AbortWithStatusJSON(http.StatusForbidden, result.ReturnErrorResult("admin access is required"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// OwnsCustomer only lets through the customer whose ID source reads, or an admin
+func OwnsCustomer(source Source) gin.HandlerFunc {
+	return OwnsResource(source, CustomerOwner)
+}
+
+// CustomerOwner resolves a customer ID to the customer themselves
+func CustomerOwner(id string) (uuid.UUID, error) {
+	return uuid.FromString(id)
+}
+
+// WalletOwner resolves the customer holding a wallet
+func WalletOwner(ws ports.IWalletService) OwnerLookup {
+	return func(id string) (uuid.UUID, error) {
+		wallet, err := ws.GetWalletByID(id)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		return wallet.Owner, nil
+	}
+}
+
+// OwnsResource only lets through the customer holding the resource source reads the id of, as
+// lookup resolves it, or an admin or service. A customer naming no resource, or one that does
+// not exist, is refused
+func OwnsResource(source Source, lookup OwnerLookup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if GetPrincipal(c) == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, result.ReturnErrorResult("authentication is required"))
+			return
+		}
+
+		id, err := source(c)
+		if err != nil || !Permits(c, id, lookup) {
+			c.AbortWithStatusJSON(http.StatusForbidden, result.ReturnErrorResult("access to this resource is forbidden"))
+			return
+		}
+		c.Next()
+	}
+}
+
+// Permits reports whether the request's principal may act on the resource with id, as lookup
+// resolves its owner. Admins and services are not bound by ownership, a customer is refused
+// anything that cannot be resolved as well as anything they do not hold
+func Permits(c *gin.Context, id string, lookup OwnerLookup) bool {
+	principal := GetPrincipal(c)
+	if principal == nil {
+		return false
+	}
+
+	if principal.IsAdmin() || principal.Service {
+		return true
+	}
+
+	if id == "" {
+		return false
+	}
+
+	owner, err := lookup(id)
+	return err == nil && principal.Owns(owner)
+}
+
+// Param reads the resource id from the path parameter name
+func Param(name string) Source {
+	return func(c *gin.Context) (string, error) {
+		return c.Param(name), nil
+	}
+}
@@ -28,6 +28,24 @@ func (r *Repository[T]) Get(pagination *utils.Pagination) (*utils.Pagination, er
 	return pagination, nil
 }
 
+func (r *Repository[T]) GetWhere(pagination *utils.Pagination, scopes ...func(db *gorm.DB) *gorm.DB) (*utils.Pagination, error) {
+	var payload []T
+	query := r.db.Scopes(scopes...).Session(&gorm.Session{})
+	if err := query.Scopes(utils.Paginate(payload, pagination, query)).Find(&payload).Error; err != nil {
+		return nil, err
+	}
+	pagination.Rows = payload
+	return pagination, nil
+}
+
+func (r *Repository[T]) GetAll() ([]T, error) {
+	var payload []T
+	if err := r.db.Find(&payload).Error; err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
 func (r *Repository[T]) GetByID(id string) (*T, error) {
 	var payload T
 	if err := r.db.Where("id = ?", id).First(&payload).Error; err != nil {
@@ -36,6 +54,65 @@ func (r *Repository[T]) GetByID(id string) (*T, error) {
 	return &payload, nil
 }
 
+func (r *Repository[T]) GetByIDPreload(id string, association string, args ...interface{}) (*T, error) {
+	var payload T
+	if err := r.db.Preload(association, args...).Where("id = ?", id).First(&payload).Error; err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+func (r *Repository[T]) GetBy(query string, args ...interface{}) (*T, error) {
+	var payload T
+	if err := r.db.Where(query, args...).First(&payload).Error; err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+func (r *Repository[T]) GetAllBy(query string, args ...interface{}) ([]T, error) {
+	var payload []T
+	if err := r.db.Where(query, args...).Find(&payload).Error; err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (r *Repository[T]) GetAllWhere(scopes ...func(db *gorm.DB) *gorm.DB) ([]T, error) {
+	var payload []T
+	if err := r.db.Scopes(scopes...).Find(&payload).Error; err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (r *Repository[T]) FindInBatches(size int, fn func(batch []T) error) error {
+	var payload []T
+	return r.db.FindInBatches(&payload, size, func(tx *gorm.DB, _ int) error {
+		return fn(payload)
+	}).Error
+}
+
+func (r *Repository[T]) SumBy(column string, group string, dest interface{}) error {
+	return r.db.Model(new(T)).Select(fmt.Sprintf("%v, COALESCE(SUM(%v), 0) AS total", group, column)).Group(group).Scan(dest).Error
+}
+
+func (r *Repository[T]) Sum(column string, query string, args ...interface{}) (int64, error) {
+	var total int64
+	if err := r.db.Model(new(T)).Select(fmt.Sprintf("COALESCE(SUM(%v), 0)", column)).Where(query, args...).Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *Repository[T]) Count(query string, args ...interface{}) (int64, error) {
+	var total int64
+	if err := r.db.Model(new(T)).Where(query, args...).Count(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
 func (r *Repository[T]) GetByIDForUpdate(id string) (*T, error) {
 	var payload T
 	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "NOWAIT"}).Where("id = ?", id).First(&payload).Error; err != nil {
@@ -75,3 +152,19 @@ func (r *Repository[T]) DeleteAll(entity string) error {
 func (r *Repository[T]) WithTx(tx *gorm.DB) *Repository[T] {
 	return NewRepository[T](tx)
 }
+
+// Unscoped returns a repository that sees soft deleted rows, and whose Delete removes rows for good
+func (r *Repository[T]) Unscoped() *Repository[T] {
+	return NewRepository[T](r.db.Unscoped())
+}
+
+func (r *Repository[T]) Restore(id string) error {
+	query := r.db.Unscoped().Model(new(T)).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -1,5 +1,10 @@
 package config
 
+import (
+	"strconv"
+	"time"
+)
+
 // Env returns the value of the environment variable named by the key.
 type Env string
 
@@ -11,6 +16,31 @@ type Config struct {
 	RedisURL    string  `env:"REDIS_URL"`
 	Env         string  `env:"ENV"`
 	ElasticURL  string  `env:"ELASTIC_URL"`
+
+	IdempotencyRetention *string `env:"IDEMPOTENCY_RETENTION"`
+	BankCode             *string `env:"BANK_CODE"`
+	FXRatesFile          *string `env:"FX_RATES_FILE"`
+	FXQuoteTTL           *string `env:"FX_QUOTE_TTL"`
+	FXSpreadBps          *string `env:"FX_SPREAD_BPS"`
+	HoldTTL              *string `env:"HOLD_TTL"`
+	HoldExpiryInterval   *string `env:"HOLD_EXPIRY_INTERVAL"`
+	WalletRetention      *string `env:"WALLET_RETENTION"`
+	WalletPurgeInterval  *string `env:"WALLET_PURGE_INTERVAL"`
+	ScheduleInterval     *string `env:"SCHEDULE_INTERVAL"`
+	ReconciliationAt     *string `env:"RECONCILIATION_AT"`
+	ReconciliationDir    *string `env:"RECONCILIATION_DIR"`
+	InterestAccrualAt    *string `env:"INTEREST_ACCRUAL_AT"`
+	InterestPayoutCycle  *string `env:"INTEREST_PAYOUT_CYCLE"`
+	JWTPublicKeyFile     *string `env:"JWT_PUBLIC_KEY_FILE"`
+	JWTIssuer            *string `env:"JWT_ISSUER"`
+	JWTAudience          *string `env:"JWT_AUDIENCE"`
+
+	AdjustmentSupervisorThreshold *string `env:"ADJUSTMENT_SUPERVISOR_THRESHOLD"`
+	ApprovalThreshold             *string `env:"APPROVAL_THRESHOLD"`
+	ApprovalTTL                   *string `env:"APPROVAL_TTL"`
+	ApprovalExpiryInterval        *string `env:"APPROVAL_EXPIRY_INTERVAL"`
+	SavingsInterestRateBps        *string `env:"SAVINGS_INTEREST_RATE_BPS"`
+	LedgerHashKey                 *string `env:"LEDGER_HASH_KEY"`
 }
 
 // GetEnv returns the current environment
@@ -18,5 +48,221 @@ func (c *Config) GetEnv() Env {
 	return Env(c.Env)
 }
 
+// GetIdempotencyRetention returns how long an idempotency key is honoured, defaulting to a day
+func (c *Config) GetIdempotencyRetention() time.Duration {
+	if c == nil {
+		return 24 * time.Hour
+	}
+	return parseDuration(c.IdempotencyRetention, 24*time.Hour)
+}
+
+// GetBankCode returns the institution code account numbers are checksummed against
+func (c *Config) GetBankCode() string {
+	if c == nil || c.BankCode == nil {
+		return "000"
+	}
+	return *c.BankCode
+}
+
+// GetFXQuoteTTL returns how long a quoted exchange rate stays valid, defaulting to 30 seconds
+func (c *Config) GetFXQuoteTTL() time.Duration {
+	if c == nil {
+		return 30 * time.Second
+	}
+	return parseDuration(c.FXQuoteTTL, 30*time.Second)
+}
+
+// GetFXSpreadBps returns the spread taken off the mid rate in basis points, defaulting to none
+func (c *Config) GetFXSpreadBps() int64 {
+	if c == nil {
+		return 0
+	}
+	return parseInt(c.FXSpreadBps, 0)
+}
+
+// GetHoldTTL returns how long a hold reserves funds when it names no expiry, defaulting to a week
+func (c *Config) GetHoldTTL() time.Duration {
+	if c == nil {
+		return 7 * 24 * time.Hour
+	}
+	return parseDuration(c.HoldTTL, 7*24*time.Hour)
+}
+
+// GetHoldExpiryInterval returns how often lapsed holds are swept, defaulting to a minute
+func (c *Config) GetHoldExpiryInterval() time.Duration {
+	if c == nil {
+		return time.Minute
+	}
+	return parseDuration(c.HoldExpiryInterval, time.Minute)
+}
+
+// GetWalletRetention returns how long a deleted wallet is kept before it may be purged, defaulting to 90 days
+func (c *Config) GetWalletRetention() time.Duration {
+	if c == nil {
+		return 90 * 24 * time.Hour
+	}
+	return parseDuration(c.WalletRetention, 90*24*time.Hour)
+}
+
+// GetWalletPurgeInterval returns how often deleted wallets past retention are purged, defaulting to a day
+func (c *Config) GetWalletPurgeInterval() time.Duration {
+	if c == nil {
+		return 24 * time.Hour
+	}
+	return parseDuration(c.WalletPurgeInterval, 24*time.Hour)
+}
+
+// GetScheduleInterval returns how often due scheduled transactions are run, defaulting to a minute
+func (c *Config) GetScheduleInterval() time.Duration {
+	if c == nil {
+		return time.Minute
+	}
+	return parseDuration(c.ScheduleInterval, time.Minute)
+}
+
+// GetReconciliationAt returns the time of day, as HH:MM, the nightly reconciliation runs at, defaulting to 02:00
+func (c *Config) GetReconciliationAt() (int, int) {
+	if c == nil {
+		return 2, 0
+	}
+	return parseClock(c.ReconciliationAt, 2, 0)
+}
+
+// GetReconciliationDir returns where reconciliation reports are written, defaulting to reports
+func (c *Config) GetReconciliationDir() string {
+	if c == nil || c.ReconciliationDir == nil || *c.ReconciliationDir == "" {
+		return "reports"
+	}
+	return *c.ReconciliationDir
+}
+
+// GetInterestAccrualAt returns the time of day, as HH:MM, the previous day's interest is accrued at, defaulting to 00:30
+func (c *Config) GetInterestAccrualAt() (int, int) {
+	if c == nil {
+		return 0, 30
+	}
+	return parseClock(c.InterestAccrualAt, 0, 30)
+}
+
+// GetInterestPayoutCycle returns how often accrued interest is paid out, daily, weekly or monthly, defaulting to monthly
+func (c *Config) GetInterestPayoutCycle() string {
+	if c == nil || c.InterestPayoutCycle == nil {
+		return "monthly"
+	}
+	switch *c.InterestPayoutCycle {
+	case "daily", "weekly", "monthly":
+		return *c.InterestPayoutCycle
+	}
+	return "monthly"
+}
+
+// GetSavingsInterestRateBps returns the annual rate, in basis points, a savings wallet is opened on, defaulting to none so savings wallets cannot be opened until it is set
+func (c *Config) GetSavingsInterestRateBps() int64 {
+	if c == nil {
+		return 0
+	}
+	return parseInt(c.SavingsInterestRateBps, 0)
+}
+
+// GetLedgerHashKey returns the key transaction hashes are keyed with. It must live outside the database, a chain anyone writing the database can recompute proves nothing
+func (c *Config) GetLedgerHashKey() string {
+	if c == nil || c.LedgerHashKey == nil {
+		return ""
+	}
+	return *c.LedgerHashKey
+}
+
+// GetJWTPublicKeyFile returns the PEM file holding the RSA key RS256 tokens are verified with, empty when only HS256 is accepted
+func (c *Config) GetJWTPublicKeyFile() string {
+	if c == nil || c.JWTPublicKeyFile == nil {
+		return ""
+	}
+	return *c.JWTPublicKeyFile
+}
+
+// GetJWTIssuer returns the issuer tokens must name, empty to accept any
+func (c *Config) GetJWTIssuer() string {
+	if c == nil || c.JWTIssuer == nil {
+		return ""
+	}
+	return *c.JWTIssuer
+}
+
+// GetJWTAudience returns the audience tokens must name, empty to accept any
+func (c *Config) GetJWTAudience() string {
+	if c == nil || c.JWTAudience == nil {
+		return ""
+	}
+	return *c.JWTAudience
+}
+
+// GetAdjustmentSupervisorThreshold returns the largest manual adjustment, in minor units, an operator may make without a supervisor, defaulting to 100000.00
+func (c *Config) GetAdjustmentSupervisorThreshold() int64 {
+	if c == nil {
+		return 10000000
+	}
+	return parseInt(c.AdjustmentSupervisorThreshold, 10000000)
+}
+
+// GetApprovalThreshold returns the largest debit, in minor units, that posts without a second person's approval, defaulting to 20000.00 so it sits below every tier's single transaction limit
+func (c *Config) GetApprovalThreshold() int64 {
+	if c == nil {
+		return 2000000
+	}
+	return parseInt(c.ApprovalThreshold, 2000000)
+}
+
+// GetApprovalTTL returns how long a debit waits for approval before it expires, defaulting to a day
+func (c *Config) GetApprovalTTL() time.Duration {
+	if c == nil {
+		return 24 * time.Hour
+	}
+	return parseDuration(c.ApprovalTTL, 24*time.Hour)
+}
+
+// GetApprovalExpiryInterval returns how often lapsed approval requests are swept, defaulting to a minute
+func (c *Config) GetApprovalExpiryInterval() time.Duration {
+	if c == nil {
+		return time.Minute
+	}
+	return parseDuration(c.ApprovalExpiryInterval, time.Minute)
+}
+
+// parseClock reads an optional HH:MM setting, falling back when it is unset or malformed
+func parseClock(value *string, hour, minute int) (int, int) {
+	if value == nil {
+		return hour, minute
+	}
+	t, err := time.Parse("15:04", *value)
+	if err != nil {
+		return hour, minute
+	}
+	return t.Hour(), t.Minute()
+}
+
+// parseDuration reads an optional duration setting, falling back when it is unset or malformed
+func parseDuration(value *string, fallback time.Duration) time.Duration {
+	if value == nil {
+		return fallback
+	}
+	d, err := time.ParseDuration(*value)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// parseInt reads an optional integer setting, falling back when it is unset or malformed
+func parseInt(value *string, fallback int64) int64 {
+	if value == nil {
+		return fallback
+	}
+	i, err := strconv.ParseInt(*value, 10, 64)
+	if err != nil || i < 0 {
+		return fallback
+	}
+	return i
+}
+
 // Instance is the global configuration
 var Instance *Config
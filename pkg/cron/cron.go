@@ -0,0 +1,133 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSpec is returned for an expression that is not a five field cron spec
+var ErrInvalidSpec = errors.New("invalid cron expression")
+
+// Schedule is a parsed five field cron expression: minute, hour, day of month, month and day of
+// week. Each field takes *, a value, a range a-b, a list a,b and a step */n or a-b/n. Sunday is
+// 0 or 7 and, as in cron, a day matches either day field when both are restricted
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+type bounds struct {
+	min, max int
+}
+
+var fields = []bounds{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// Parse reads spec into a Schedule
+func Parse(spec string) (*Schedule, error) {
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("%w: want %d fields, got %d", ErrInvalidSpec, len(fields), len(parts))
+	}
+
+	sets := make([]uint64, len(fields))
+	for i, part := range parts {
+		set, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+
+	// both 0 and 7 name Sunday
+	if sets[4]&(1<<7) != 0 {
+		sets[4] |= 1
+	}
+
+	return &Schedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4],
+		domAny: parts[2] == "*",
+		dowAny: parts[4] == "*",
+	}, nil
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var set uint64
+	for _, item := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%w: bad step in %q", ErrInvalidSpec, item)
+			}
+			step, item = n, item[:i]
+		}
+
+		low, high := b.min, b.max
+		switch {
+		case item == "*":
+		case strings.Contains(item, "-"):
+			ends := strings.SplitN(item, "-", 2)
+			var err error
+			if low, err = strconv.Atoi(ends[0]); err != nil {
+				return 0, fmt.Errorf("%w: bad range %q", ErrInvalidSpec, item)
+			}
+			if high, err = strconv.Atoi(ends[1]); err != nil {
+				return 0, fmt.Errorf("%w: bad range %q", ErrInvalidSpec, item)
+			}
+		default:
+			value, err := strconv.Atoi(item)
+			if err != nil {
+				return 0, fmt.Errorf("%w: bad value %q", ErrInvalidSpec, item)
+			}
+			low, high = value, value
+		}
+
+		if low < b.min || high > b.max || low > high {
+			return 0, fmt.Errorf("%w: %q outside %d-%d", ErrInvalidSpec, item, b.min, b.max)
+		}
+
+		for v := low; v <= high; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// Next returns the first minute strictly after t the schedule fires at, in t's location, or the
+// zero time when it never fires within the next five years, as with the 31st of February
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
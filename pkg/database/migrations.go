@@ -0,0 +1,194 @@
+package database
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+	"wallet_engine/pkg/config"
+	"wallet_engine/pkg/nuban"
+)
+
+// renumberAttempts is how many account numbers are tried before giving up on a wallet
+const renumberAttempts = 10
+
+// renumberSharedAccounts gives every wallet sharing an account number a new one, so the unique
+// index on account numbers can be built over wallets created before it existed. It runs before
+// the schema is migrated. None of the wallets sharing a number can be told apart as its rightful
+// holder, so all of them move, and transactions booked against the shared number stay unlinked
+// for manual review
+func renumberSharedAccounts(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&domain.Wallet{}) {
+		return nil
+	}
+
+	var shared []int64
+	err := db.Unscoped().Model(&domain.Wallet{}).
+		Where("account_id IS NOT NULL").
+		Group("account_id").
+		Having("COUNT(*) > 1").
+		Pluck("account_id", &shared).Error
+	if err != nil || len(shared) == 0 {
+		return err
+	}
+
+	accountNumbers := nuban.NewNUBAN(config.Instance.GetBankCode())
+
+	return db.Transaction(func(t *gorm.DB) error {
+		var ids []string
+		if err := t.Unscoped().Model(&domain.Wallet{}).Where("account_id IN ?", shared).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			accountNumber, err := unusedAccountNumber(t, accountNumbers)
+			if err != nil {
+				return err
+			}
+
+			if err := t.Unscoped().Model(&domain.Wallet{}).Where("id = ?", id).Update("account_id", accountNumber).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// unusedAccountNumber generates an account number no wallet holds, deleted wallets included
+func unusedAccountNumber(db *gorm.DB, accountNumbers ports.IAccountNumberGenerator) (int64, error) {
+	for attempt := 0; attempt < renumberAttempts; attempt++ {
+		accountNumber, err := accountNumbers.Generate()
+		if err != nil {
+			return 0, err
+		}
+
+		var holders int64
+		if err := db.Unscoped().Model(&domain.Wallet{}).Where("account_id = ?", accountNumber).Count(&holders).Error; err != nil {
+			return 0, err
+		}
+		if holders == 0 {
+			return accountNumber, nil
+		}
+	}
+	return 0, domain.ErrAccountNumberExhausted
+}
+
+// backfillTransactionWallets links transactions written before wallet_id existed to their wallet.
+// Rows are only linked when exactly one wallet holds the account number, ambiguous rows are left
+// unlinked for manual review rather than guessed
+func backfillTransactionWallets(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE transactions
+		SET wallet_id = (SELECT wallets.id FROM wallets WHERE wallets.account_id = transactions.account_id)
+		WHERE wallet_id IS NULL
+		AND (SELECT COUNT(*) FROM wallets WHERE wallets.account_id = transactions.account_id) = 1
+	`).Error
+}
+
+// backfillChainHeads records the hash of each wallet's latest transaction as its chain head for
+// wallets whose transactions were sealed before heads were kept
+func backfillChainHeads(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE wallets
+		SET chain_head = COALESCE((
+			SELECT transactions.hash FROM transactions
+			WHERE transactions.wallet_id = wallets.id
+			ORDER BY transactions.created_at DESC LIMIT 1
+		), '')
+		WHERE chain_head = ''
+	`).Error
+}
+
+// seedTierLimits installs the default tier limits the first time the engine starts, after that
+// the stored limits are the source of truth
+func seedTierLimits(db *gorm.DB) error {
+	var count int64
+	if err := db.Model(&domain.TierLimit{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	limits := append([]domain.TierLimit{}, domain.DefaultTierLimits...)
+	return db.Create(&limits).Error
+}
+
+// seedChartOfAccounts opens every internal account of the chart in every supported currency.
+// It runs on each start and only inserts what is missing, so new accounts or currencies appear
+// without touching existing ones
+func seedChartOfAccounts(db *gorm.DB) error {
+	var accounts []domain.LedgerAccount
+	for _, currency := range domain.Currencies() {
+		for _, account := range domain.ChartOfAccounts {
+			account.Currency = currency
+			accounts = append(accounts, account)
+		}
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&accounts).Error
+}
+
+// postOpeningBalances journals the balance of every wallet that holds one but has never been
+// posted to the general ledger, as happens to wallets funded before the ledger existed. The
+// balance is credited to the wallet's liability account against settlement, once, so the ledger
+// agrees with the wallets from then on. It runs on each start after the chart of accounts is seeded
+func postOpeningBalances(db *gorm.DB) error {
+	var wallets []domain.Wallet
+	err := db.Where("balance <> 0").
+		Where(`NOT EXISTS (
+			SELECT 1 FROM ledger_accounts
+			JOIN postings ON postings.ledger_account_id = ledger_accounts.id
+			WHERE ledger_accounts.wallet_id = wallets.id
+		)`).
+		Find(&wallets).Error
+	if err != nil || len(wallets) == 0 {
+		return err
+	}
+
+	return db.Transaction(func(t *gorm.DB) error {
+		for _, wallet := range wallets {
+			if err := postOpeningBalance(t, wallet); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// postOpeningBalance journals the balance of wallet against settlement, opening its liability
+// account when it has none
+func postOpeningBalance(db *gorm.DB, wallet domain.Wallet) error {
+	var account domain.LedgerAccount
+	err := db.Where(domain.LedgerAccount{WalletID: &wallet.ID}).
+		Attrs(domain.LedgerAccount{
+			Code:     fmt.Sprintf("%v-%d", domain.WALLET_ACCOUNT, wallet.AccountID),
+			Name:     fmt.Sprintf("Wallet %d", wallet.AccountID),
+			Type:     domain.LIABILITY,
+			Currency: wallet.Currency,
+		}).
+		FirstOrCreate(&account).Error
+	if err != nil {
+		return err
+	}
+
+	var settlement domain.LedgerAccount
+	if err := db.Where("code = ? AND currency = ?", domain.SETTLEMENT_ACCOUNT, wallet.Currency).First(&settlement).Error; err != nil {
+		return fmt.Errorf("ledger account %v %v: %w", domain.SETTLEMENT_ACCOUNT, wallet.Currency, err)
+	}
+
+	entry := &domain.JournalEntry{
+		Reference:   fmt.Sprintf("opening:%v", wallet.ID),
+		Description: fmt.Sprintf("opening balance of wallet %d", wallet.AccountID),
+		Postings: []domain.Posting{
+			{LedgerAccountID: account.ID, Amount: -wallet.Balance, Currency: wallet.Currency},
+			{LedgerAccountID: settlement.ID, Amount: wallet.Balance, Currency: wallet.Currency},
+		},
+	}
+	if !entry.Balanced() {
+		return domain.ErrUnbalancedEntry
+	}
+	return db.Create(entry).Error
+}
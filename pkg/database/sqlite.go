@@ -33,7 +33,57 @@ func (d *sqliteDatastore) ConnectDB(dsn string) *gorm.DB {
 }
 
 func (d *sqliteDatastore) MigrateAll(db *gorm.DB) error {
-	return db.AutoMigrate(&domain.Wallet{})
+	err := renumberSharedAccounts(db)
+	if err != nil {
+		return err
+	}
+
+	err = db.AutoMigrate(
+		&domain.Wallet{},
+		&domain.Transaction{},
+		&domain.IdempotencyKey{},
+		&domain.FXQuote{},
+		&domain.Hold{},
+		&domain.TierLimit{},
+		&domain.WalletStatusChange{},
+		&domain.LedgerAccount{},
+		&domain.JournalEntry{},
+		&domain.Posting{},
+		&domain.FeeSchedule{},
+		&domain.FeeBand{},
+		&domain.ScheduledTransaction{},
+		&domain.ScheduledExecution{},
+		&domain.InterestAccrual{},
+		&domain.Customer{},
+		&domain.APIKey{},
+		&domain.AuditLog{},
+		&domain.ApprovalRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	err = backfillTransactionWallets(db)
+	if err != nil {
+		return err
+	}
+
+	err = backfillChainHeads(db)
+	if err != nil {
+		return err
+	}
+
+	err = seedTierLimits(db)
+	if err != nil {
+		return err
+	}
+
+	err = seedChartOfAccounts(db)
+	if err != nil {
+		return err
+	}
+
+	return postOpeningBalances(db)
 }
 
 func (d *sqliteDatastore) DropAll(db *gorm.DB) error {
@@ -0,0 +1,58 @@
+package fx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"wallet_engine/internals/core/domain"
+	"wallet_engine/internals/core/ports"
+)
+
+type staticRateProvider struct {
+	rates map[string]*big.Rat
+}
+
+// NewStaticRateProvider creates a rate provider from fixed decimal rates keyed by "FROM/TO",
+// e.g. {"USD/NGN": "1450.50"}. The inverse of every pair is derived, so one direction is enough
+func NewStaticRateProvider(rates map[string]string) (ports.IFXRateProvider, error) {
+	p := &staticRateProvider{rates: map[string]*big.Rat{}}
+	for pair, value := range rates {
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid rate %q for %s", value, pair)
+		}
+		p.rates[strings.ToUpper(pair)] = rate
+	}
+	return p, nil
+}
+
+// LoadStaticRateProvider creates a rate provider from a JSON file holding the same shape
+// NewStaticRateProvider accepts
+func LoadStaticRateProvider(path string) (ports.IFXRateProvider, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rates map[string]string
+	if err := json.Unmarshal(content, &rates); err != nil {
+		return nil, err
+	}
+	return NewStaticRateProvider(rates)
+}
+
+func (p *staticRateProvider) Rate(from, to domain.Currency) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+	if rate, ok := p.rates[fmt.Sprintf("%v/%v", from, to)]; ok {
+		return new(big.Rat).Set(rate), nil
+	}
+	if rate, ok := p.rates[fmt.Sprintf("%v/%v", to, from)]; ok {
+		return new(big.Rat).Inv(rate), nil
+	}
+	return nil, domain.ErrRateUnavailable
+}
@@ -0,0 +1,62 @@
+package nuban
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"wallet_engine/internals/core/ports"
+)
+
+// weights applied to the 6 digit institution code followed by the 9 digit serial number
+const weights = "373373373373373"
+
+const (
+	minSerial = 100000000
+	maxSerial = 999999999
+)
+
+type nuban struct {
+	institutionCode string
+}
+
+// NewNUBAN creates a NUBAN style account number generator for the given institution code.
+// Shorter codes, like the legacy 3 digit bank codes, are left padded with zeros
+func NewNUBAN(institutionCode string) ports.IAccountNumberGenerator {
+	return &nuban{
+		institutionCode: fmt.Sprintf("%06s", institutionCode),
+	}
+}
+
+// Generate returns a random 9 digit serial number followed by its check digit
+func (n *nuban) Generate() (int64, error) {
+	serial, err := rand.Int(rand.Reader, big.NewInt(maxSerial-minSerial+1))
+	if err != nil {
+		return 0, err
+	}
+	s := serial.Int64() + minSerial
+	return s*10 + n.checkDigit(s), nil
+}
+
+// Valid reports whether accountNumber is 10 digits long and carries the right check digit
+func (n *nuban) Valid(accountNumber int64) bool {
+	if accountNumber < minSerial*10 || accountNumber > maxSerial*10+9 {
+		return false
+	}
+	return accountNumber%10 == n.checkDigit(accountNumber/10)
+}
+
+func (n *nuban) checkDigit(serial int64) int64 {
+	digits := n.institutionCode + fmt.Sprintf("%09d", serial)
+
+	var sum int64
+	for i := 0; i < len(digits); i++ {
+		sum += int64(digits[i]-'0') * int64(weights[i]-'0')
+	}
+
+	check := 10 - sum%10
+	if check == 10 {
+		return 0
+	}
+	return check
+}
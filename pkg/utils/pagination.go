@@ -5,10 +5,13 @@ import (
 	"math"
 )
 
+// MaxLimit is the largest page a caller can ask for
+const MaxLimit = 100
+
 // Pagination manages pagination
 type Pagination struct {
-	Limit      int         `form:"limit,default=5" binding:"min=5"`
-	Page       int         `form:"page,default=1" binding:"min=1"`
+	Limit      int         `json:"limit" form:"limit,default=5" binding:"min=5"`
+	Page       int         `json:"page" form:"page,default=1" binding:"min=1"`
 	Sort       string      `json:"sort"`
 	TotalRows  int64       `json:"total_rows"`
 	TotalPages int         `json:"total_pages"`
@@ -20,11 +23,14 @@ func (p *Pagination) GetOffset() int {
 	return (p.GetPage() - 1) * p.GetLimit()
 }
 
-// GetLimit assigns default value to limit if it is 0
+// GetLimit assigns default value to limit if it is 0 and clamps it to MaxLimit
 func (p *Pagination) GetLimit() int {
 	if p.Limit == 0 {
 		p.Limit = 10
 	}
+	if p.Limit > MaxLimit {
+		p.Limit = MaxLimit
+	}
 	return p.Limit
 }
 
@@ -50,7 +56,7 @@ func Paginate(value interface{}, pagination *Pagination, db *gorm.DB) func(db *g
 	db.Model(value).Count(&totalRows)
 
 	pagination.TotalRows = totalRows
-	pagination.TotalPages = int(math.Ceil(float64(totalRows) / float64(pagination.Limit)))
+	pagination.TotalPages = int(math.Ceil(float64(totalRows) / float64(pagination.GetLimit())))
 
 	return func(db *gorm.DB) *gorm.DB {
 		return db.Offset(pagination.GetOffset()).Limit(pagination.GetLimit()).Order(pagination.GetSort())
@@ -0,0 +1,28 @@
+package worker
+
+import "time"
+
+// Every runs job straight away and then once per interval for as long as the process lives.
+// Runs never overlap, a slow job simply delays the next tick
+func Every(interval time.Duration, job func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		job()
+		<-ticker.C
+	}
+}
+
+// Daily runs job every day at hour:minute local time for as long as the process lives
+func Daily(hour, minute int, job func()) {
+	for {
+		now := time.Now()
+		next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		time.Sleep(time.Until(next))
+		job()
+	}
+}